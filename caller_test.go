@@ -0,0 +1,87 @@
+package clio_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"testing"
+
+	"github.com/common-fate/clio"
+	"github.com/common-fate/clio/clierr"
+	"github.com/common-fate/clio/cliolog"
+)
+
+func TestCallerShownAtDebug(t *testing.T) {
+	var b bytes.Buffer
+	clio.SetWriter(&b)
+	clio.NoColor = true
+
+	restore := clio.SetLevelFromString("debug")
+	defer restore()
+
+	clio.Info("package function")
+	packageLine := callerLine() - 1
+	clio.Named("aws").Infof("logger method")
+	loggerLine := callerLine() - 1
+	clio.Named("aws").Tracef("not shown")
+	clio.InfoCtx(context.Background(), "context function")
+	ctxLine := callerLine() - 1
+	clio.Log("output")
+	clierr.New("cli error", clierr.Warn("printer")).PrintCLIError()
+	clierrLine := callerLine() - 1
+
+	restoreStd := clio.RedirectStdLog(clio.Level.Level())
+	log.Print("standard library")
+	stdLine := callerLine() - 1
+	restoreStd()
+
+	want := fmt.Sprintf("[i] package function caller_test.go:%d\n", packageLine) +
+		fmt.Sprintf("[i] logger method caller_test.go:%d\n", loggerLine) +
+		fmt.Sprintf("[i] context function caller_test.go:%d\n", ctxLine) +
+		fmt.Sprintf("[✘] cli error caller_test.go:%d\n", clierrLine) +
+		fmt.Sprintf("    at clio_test.TestCallerShownAtDebug (caller_test.go:%d)\n", clierrLine) +
+		fmt.Sprintf("[!] printer caller_test.go:%d\n", clierrLine) +
+		fmt.Sprintf("[DEBUG] standard library caller_test.go:%d\n", stdLine)
+	if got := b.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestCallerHiddenAtInfo(t *testing.T) {
+	var b bytes.Buffer
+	clio.SetWriter(&b)
+	clio.NoColor = true
+
+	restore := clio.SetLevelFromString("info")
+	defer restore()
+
+	clio.Info("my message")
+
+	if got, want := b.String(), "[i] my message\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestCallerAlways(t *testing.T) {
+	var b bytes.Buffer
+	clio.SetWriter(&b)
+	clio.NoColor = true
+
+	restore := clio.Configure(cliolog.WithCaller(cliolog.CallerAlways))
+	defer restore()
+
+	clio.Warnw("my message", "key", "value")
+	line := callerLine() - 1
+
+	if got, want := b.String(), fmt.Sprintf("[!] my message caller_test.go:%d  \tkey:value\n", line); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// callerLine returns the line number of the call to callerLine.
+func callerLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
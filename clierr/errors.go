@@ -164,7 +164,12 @@ func (e *Err) Error() string {
 //	// produces
 //	[✘] new error
 //	hello world
+//
+// If a flight recorder has been enabled with clio.EnableRecorder, the recent
+// debug output is printed before the error.
 func (e *Err) PrintCLIError() {
+	clio.DumpRecent()
+
 	if !e.ExcludeDefaultError {
 		clio.Error(e.Err)
 	}
@@ -0,0 +1,103 @@
+package cliolog
+
+import (
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// WithAlignedFields pads messages so that the fields of consecutive messages
+// line up in a column:
+//
+//	[i] checking account   	account:123456789012
+//	[i] checked            	account:123456789012 roles:3
+//	[!] no access          	account:210987654321
+//
+// Fields start after the widest message in the current burst of messages,
+// which ends when no message has been logged for a second. Messages which
+// are wider than half of the terminal aren't padded and don't widen the
+// column, so that a single long message doesn't push the fields of the
+// other messages off the screen.
+func WithAlignedFields() Option {
+	return func(o *Options) {
+		o.AlignFields = true
+	}
+}
+
+// alignBurst is the longest gap between messages in the same burst.
+// The field column is reset when there is a longer gap between messages.
+const alignBurst = time.Second
+
+// defaultWidth is the width of the output when the writer isn't a terminal,
+// or its width can't be detected.
+const defaultWidth = 80
+
+// aligner tracks the column which fields start at for a writer.
+// It's shared by the clones of an encoder.
+type aligner struct {
+	w io.Writer
+
+	mu sync.Mutex
+	// col is the column fields start at in the current burst.
+	col int
+	// last is the time of the previous aligned message.
+	last time.Time
+	// max is the widest column in the current burst. It's read from the
+	// terminal at the start of each burst rather than for every message.
+	max int
+}
+
+func newAligner(w io.Writer) *aligner {
+	return &aligner{w: w}
+}
+
+// column returns the column that fields should start at for a message
+// ending at the given column and logged at time t.
+func (a *aligner) column(width int, t time.Time) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.max == 0 || t.Sub(a.last) > alignBurst || t.Before(a.last) {
+		a.col = 0
+		a.max = a.maxColumn()
+	}
+	a.last = t
+
+	if width > a.max {
+		return width
+	}
+	if width > a.col {
+		a.col = width
+	}
+	return a.col
+}
+
+// maxColumn is the widest column that fields are aligned to.
+func (a *aligner) maxColumn() int {
+	width := terminalWidth(a.w)
+	if width <= 0 {
+		width = defaultWidth
+	}
+	return width / 2
+}
+
+// lineWidth returns the number of characters shown on the last line of b,
+// ignoring ANSI escape sequences.
+func lineWidth(b []byte) int {
+	n := 0
+	for i := 0; i < len(b); {
+		switch {
+		case b[i] == '\n':
+			n = 0
+			i++
+		case escapeLen(b[i:]) > 0:
+			i += escapeLen(b[i:])
+		default:
+			_, size := utf8.DecodeRune(b[i:])
+			n++
+			i += size
+		}
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package cliolog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestAlignedFields(t *testing.T) {
+	var b bytes.Buffer
+	noColor := true
+	log := New(zap.NewAtomicLevelAt(zap.InfoLevel), WithWriter(&b), WithNoColor(&noColor), WithAlignedFields())
+
+	log.Info("checking account", zap.String("account", "1"))
+	log.Info("checked", zap.String("account", "1"), zap.Int("roles", 3))
+	log.Info("no fields")
+	log.Warn("no access", zap.String("account", "2"))
+	// wider than half of the 80 column default width.
+	log.Info(strings.Repeat("x", 40), zap.String("account", "3"))
+	log.Info("done", zap.String("account", "3"))
+
+	want := "[i] checking account  \taccount:1\n" +
+		"[i] checked           \taccount:1\troles:3\n" +
+		"[i] no fields\n" +
+		"[!] no access         \taccount:2\n" +
+		"[i] " + strings.Repeat("x", 40) + "  \taccount:3\n" +
+		"[i] done              \taccount:3\n"
+
+	if got := b.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAlignerBurst(t *testing.T) {
+	a := newAligner(&bytes.Buffer{})
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		width int
+		t     time.Time
+		want  int
+	}{
+		{width: 10, t: start, want: 10},
+		{width: 20, t: start.Add(500 * time.Millisecond), want: 20},
+		{width: 5, t: start.Add(time.Second), want: 20},
+		// a new burst starts after a gap.
+		{width: 5, t: start.Add(3 * time.Second), want: 5},
+		// wider than the maximum column.
+		{width: 50, t: start.Add(3 * time.Second), want: 50},
+		{width: 8, t: start.Add(3 * time.Second), want: 8},
+	}
+	for i, tt := range tests {
+		if got := a.column(tt.width, tt.t); got != tt.want {
+			t.Errorf("%d: column(%d) = %d, want %d", i, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestAlignerReadsWidthPerBurst(t *testing.T) {
+	a := newAligner(&bytes.Buffer{})
+	start := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	a.column(10, start)
+	if a.max != defaultWidth/2 {
+		t.Fatalf("max = %d, want %d", a.max, defaultWidth/2)
+	}
+
+	// the width isn't read again during the burst.
+	a.max = 100
+	if got := a.column(60, start.Add(time.Millisecond)); got != 60 {
+		t.Errorf("column(60) = %d, want 60", got)
+	}
+	if got := a.column(10, start.Add(2*time.Millisecond)); got != 60 {
+		t.Errorf("column(10) = %d, want 60", got)
+	}
+
+	// it's read again when the next burst starts.
+	a.column(10, start.Add(3*time.Second))
+	if a.max != defaultWidth/2 {
+		t.Errorf("max = %d after a new burst, want %d", a.max, defaultWidth/2)
+	}
+}
+
+func TestLineWidth(t *testing.T) {
+	tests := []struct {
+		give string
+		want int
+	}{
+		{give: "[i] hello", want: 9},
+		{give: "\x1b[0m\x1b[38;5;240m[✘] hello\x1b[0m", want: 9},
+		{give: "first line\nsecond", want: 6},
+	}
+	for _, tt := range tests {
+		if got := lineWidth([]byte(tt.give)); got != tt.want {
+			t.Errorf("lineWidth(%q) = %d, want %d", tt.give, got, tt.want)
+		}
+	}
+}
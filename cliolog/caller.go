@@ -0,0 +1,120 @@
+package cliolog
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// CallerMode controls when the caller of a message is shown on the console.
+type CallerMode int
+
+const (
+	// CallerNever never shows the caller. It's the default.
+	CallerNever CallerMode = iota
+	// CallerAuto shows the caller when debug messages are enabled for the logger,
+	// such as when the logging level is debug or trace.
+	CallerAuto
+	// CallerAlways always shows the caller.
+	CallerAlways
+)
+
+// WithCaller shows the location of the code which logged each message,
+// as a dimmed file.go:123 after the message on the console.
+//
+// The caller is the first function outside of clio, zap and the standard
+// library's log packages, so messages logged through wrappers such as
+// clio.Infof, clio.Logger and clierr.Err are attributed to the code which
+// called the wrapper.
+func WithCaller(mode CallerMode) Option {
+	return func(o *Options) {
+		o.Caller = mode
+	}
+}
+
+// callerCore sets the caller of entries written to the wrapped core.
+type callerCore struct {
+	zapcore.Core
+	// show returns true if the caller of the entry should be shown.
+	show func(ent zapcore.Entry) bool
+}
+
+func newCallerCore(core zapcore.Core, o Options, level zapcore.LevelEnabler) zapcore.Core {
+	switch o.Caller {
+	case CallerAlways:
+		return &callerCore{Core: core, show: func(zapcore.Entry) bool { return true }}
+	case CallerAuto:
+		show := func(zapcore.Entry) bool { return level.Enabled(zapcore.DebugLevel) }
+		if o.NamedLevels != nil {
+			show = func(ent zapcore.Entry) bool {
+				return o.NamedLevels.EnabledFor(ent.LoggerName, zapcore.DebugLevel)
+			}
+		}
+		return &callerCore{Core: core, show: show}
+	default:
+		return core
+	}
+}
+
+// With implements zapcore.Core
+func (c *callerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &callerCore{Core: c.Core.With(fields), show: c.show}
+}
+
+// Check implements zapcore.Core
+func (c *callerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checkWrapped(c.Core, ent, ce, c.setCaller)
+}
+
+// setCaller sets the caller of an entry which is being written, if it's shown.
+func (c *callerCore) setCaller(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	if c.show(ent) {
+		// entries are written synchronously, so the caller is on the stack.
+		ent.Caller = findCaller()
+	}
+	return ent, fields
+}
+
+// clioPackage is the import path of the clio module, such as github.com/common-fate/clio.
+var clioPackage = strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(New).Pointer()).Name(), "/cliolog.New")
+
+// findCaller returns the first caller on the stack which isn't part of a logging package.
+func findCaller() zapcore.EntryCaller {
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	for {
+		f, more := frames.Next()
+		if !isLoggingFrame(f) {
+			return zapcore.EntryCaller{
+				Defined:  true,
+				PC:       f.PC,
+				File:     f.File,
+				Line:     f.Line,
+				Function: f.Function,
+			}
+		}
+		if !more {
+			return zapcore.EntryCaller{}
+		}
+	}
+}
+
+// isLoggingFrame returns true if the frame is part of clio, zap or the
+// standard library's log packages. Tests in the clio packages aren't
+// treated as logging frames.
+func isLoggingFrame(f runtime.Frame) bool {
+	fn := f.Function
+	switch {
+	case strings.HasPrefix(fn, clioPackage+".") || strings.HasPrefix(fn, clioPackage+"/"):
+		return !strings.HasSuffix(f.File, "_test.go")
+	case strings.HasPrefix(fn, "go.uber.org/zap"):
+		return true
+	case strings.HasPrefix(fn, "log.") || strings.HasPrefix(fn, "log/slog."):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package cliolog
+
+import (
+	"os"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// kindCore drops messages of kinds which have been disabled
+// with SetKindEnabled.
+type kindCore struct {
+	zapcore.Core
+	// kind is the kind set on the logger with With(), if any.
+	kind string
+}
+
+func newKindCore(core zapcore.Core) zapcore.Core {
+	return &kindCore{Core: core}
+}
+
+// With implements zapcore.Core
+func (c *kindCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	for _, f := range fields {
+		if isKindField(f) {
+			clone.kind = f.String
+		}
+	}
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *kindCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write implements zapcore.Core
+func (c *kindCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	k, ok := kindFromFields(fields)
+	if !ok {
+		k, ok = LookupKind(entryKind(ent, c.kind))
+	}
+	if ok && !k.Enabled() {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// entryKind returns the kind of an entry given the kind set on the logger
+// with With(). Entries logged to the deprecated SuccessName logger
+// are treated as SuccessKind.
+func entryKind(ent zapcore.Entry, kind string) string {
+	if kind == "" && hasName(ent.LoggerName, SuccessName) {
+		return SuccessKind
+	}
+	return kind
+}
+
+// splitCore sends output messages, which are logged to the NoPrefixName
+// logger, to the output core, and all other messages to the status core.
+type splitCore struct {
+	status zapcore.Core
+	output zapcore.Core
+}
+
+// Enabled implements zapcore.Core
+func (c *splitCore) Enabled(level zapcore.Level) bool {
+	return c.status.Enabled(level) || c.output.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *splitCore) With(fields []zapcore.Field) zapcore.Core {
+	return &splitCore{
+		status: c.status.With(fields),
+		output: c.output.With(fields),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *splitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return c.route(ent).Check(ent, ce)
+}
+
+// Write implements zapcore.Core
+func (c *splitCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.route(ent).Write(ent, fields)
+}
+
+// Sync implements zapcore.Core
+func (c *splitCore) Sync() error {
+	return multierr.Append(c.status.Sync(), c.output.Sync())
+}
+
+func (c *splitCore) route(ent zapcore.Entry) zapcore.Core {
+	if hasName(ent.LoggerName, NoPrefixName) {
+		return c.output
+	}
+	return c.status
+}
+
+// errorOutput is where errors writing entries to the cores beneath a
+// wrapping core are reported, which is stderr as for zap loggers.
+var errorOutput zapcore.WriteSyncer = zapcore.Lock(os.Stderr)
+
+// updateFunc updates an entry and its fields before they're written.
+type updateFunc func(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field)
+
+// checkWrapped checks an entry with the wrapped core. If it's accepted, a
+// core is added to ce which calls update and then writes the entry to the
+// cores which accepted it when it was checked.
+//
+// Entries are updated when they're written rather than when they're checked,
+// as zap adds the caller and stack trace to the entry after checking it.
+func checkWrapped(core zapcore.Core, ent zapcore.Entry, ce *zapcore.CheckedEntry, update updateFunc) *zapcore.CheckedEntry {
+	inner := core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(ent, &wrappedWriter{Core: core, inner: inner, update: update})
+}
+
+// wrappedWriter updates an entry and writes it to the cores which accepted it
+// when it was checked.
+type wrappedWriter struct {
+	zapcore.Core
+	inner  *zapcore.CheckedEntry
+	update updateFunc
+}
+
+// Write implements zapcore.Core
+func (w *wrappedWriter) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	ent, fields = w.update(ent, fields)
+	w.inner.Entry = ent
+	// the inner checked entry isn't written by the logger, so it doesn't
+	// have the logger's error output set.
+	w.inner.ErrorOutput = errorOutput
+	w.inner.Write(fields...)
+	return nil
+}
@@ -0,0 +1,38 @@
+package cliolog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestWrappedWriteErrors(t *testing.T) {
+	var out bytes.Buffer
+	defer func(w zapcore.WriteSyncer) { errorOutput = w }(errorOutput)
+	errorOutput = zapcore.AddSync(&out)
+
+	noColor := true
+	log := New(zap.NewAtomicLevelAt(zapcore.InfoLevel),
+		WithWriter(failingWriter{}),
+		WithNoColor(&noColor),
+		WithHook(func(zapcore.Entry, []zapcore.Field) {}),
+		func(o *Options) {
+			o.Redactor = NewRedactor()
+			o.Caller = CallerAlways
+		},
+	)
+	log.Info("hello")
+
+	if got := out.String(); strings.Count(got, "write error: disk full") != 1 {
+		t.Errorf("error output = %q, want the write error once", got)
+	}
+}
@@ -0,0 +1,263 @@
+package cliolog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Format is the format of a log which is read with a Decoder.
+type Format string
+
+const (
+	// FormatJSON is the JSON format used by the file log by default.
+	FormatJSON Format = "json"
+	// FormatLTSV is the format written by NewLTSVEncoder.
+	FormatLTSV Format = "ltsv"
+	// FormatLogfmt is the format written by NewLogfmtEncoder.
+	FormatLogfmt Format = "logfmt"
+)
+
+// Entry is a log entry which has been read back with a Decoder.
+type Entry struct {
+	Time       time.Time
+	Level      zapcore.Level
+	LoggerName string
+	Message    string
+	Caller     string
+	Stack      string
+	// Fields are the entry's fields, other than the time, level, logger name,
+	// message, caller and stack. Values decoded from JSON have their JSON types,
+	// with numbers as json.Number, and values decoded from LTSV and logfmt are strings.
+	Fields map[string]any
+}
+
+// Decoder reads entries from a log written by clio, one entry per line.
+type Decoder struct {
+	// Keys are the keys of the time, level, logger name, message, caller and
+	// stack trace in the log. By default they're the keys used by the file log.
+	Keys zapcore.EncoderConfig
+
+	format  Format
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewDecoder returns a decoder which reads entries in the format from r.
+func NewDecoder(r io.Reader, format Format) *Decoder {
+	scanner := bufio.NewScanner(r)
+	// allow long lines, such as entries with stack traces.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return &Decoder{Keys: machineEncoderConfig(), format: format, scanner: scanner}
+}
+
+// Decode reads the next entry. Blank lines are skipped.
+// It returns io.EOF when there are no more entries.
+func (d *Decoder) Decode() (Entry, error) {
+	for d.scanner.Scan() {
+		d.line++
+		line := bytes.TrimRight(d.scanner.Bytes(), "\r")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		ent, err := d.decodeLine(line)
+		if err != nil {
+			return Entry{}, fmt.Errorf("line %d: %w", d.line, err)
+		}
+		return ent, nil
+	}
+	if err := d.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	return Entry{}, io.EOF
+}
+
+// DecodeAll reads all of the remaining entries.
+func (d *Decoder) DecodeAll() ([]Entry, error) {
+	var entries []Entry
+	for {
+		ent, err := d.Decode()
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, ent)
+	}
+}
+
+// decodeLine decodes a line in the decoder's format.
+func (d *Decoder) decodeLine(line []byte) (Entry, error) {
+	var fields map[string]any
+	switch d.format {
+	case FormatJSON:
+		dec := json.NewDecoder(bytes.NewReader(line))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return Entry{}, err
+		}
+	case FormatLTSV:
+		fields = parseLTSV(string(line))
+	case FormatLogfmt:
+		var err error
+		fields, err = parseLogfmt(string(line))
+		if err != nil {
+			return Entry{}, err
+		}
+	default:
+		return Entry{}, fmt.Errorf("unsupported log format: %q", d.format)
+	}
+	return d.entry(fields)
+}
+
+// entry builds an entry from the decoded fields of a line.
+func (d *Decoder) entry(fields map[string]any) (Entry, error) {
+	var ent Entry
+	var err error
+
+	if v, ok := take(fields, d.Keys.TimeKey); ok {
+		if ent.Time, err = parseTime(v); err != nil {
+			return Entry{}, err
+		}
+	}
+	if v, ok := take(fields, d.Keys.LevelKey); ok {
+		if ent.Level, err = ParseLevel(fmt.Sprint(v)); err != nil {
+			return Entry{}, err
+		}
+	}
+	if v, ok := take(fields, d.Keys.NameKey); ok {
+		ent.LoggerName = fmt.Sprint(v)
+	}
+	if v, ok := take(fields, d.Keys.MessageKey); ok {
+		ent.Message = fmt.Sprint(v)
+	}
+	if v, ok := take(fields, d.Keys.CallerKey); ok {
+		ent.Caller = fmt.Sprint(v)
+	}
+	if v, ok := take(fields, d.Keys.StacktraceKey); ok {
+		ent.Stack = fmt.Sprint(v)
+	}
+
+	if len(fields) > 0 {
+		ent.Fields = fields
+	}
+	return ent, nil
+}
+
+// take removes a key from fields and returns its value.
+func take(fields map[string]any, key string) (any, bool) {
+	if key == "" {
+		return nil, false
+	}
+	v, ok := fields[key]
+	delete(fields, key)
+	return v, ok
+}
+
+// timeLayouts are the layouts which times are parsed with.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05Z0700",
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+}
+
+// parseTime parses a time encoded by the file log, an ISO8601 or RFC3339
+// time, or a number of seconds since the epoch.
+func parseTime(v any) (time.Time, error) {
+	s := fmt.Sprint(v)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	if secs, err := strconv.ParseFloat(s, 64); err == nil {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time: %q", s)
+}
+
+// ltsvUnescaper reverses the escaping of tabs and newlines in LTSV values.
+var ltsvUnescaper = strings.NewReplacer(`\n`, "\n", `\r`, "\r", `\t`, "\t")
+
+// parseLTSV parses a line of LTSV. Labels without a value separator are
+// ignored. Backslashes aren't escaped by the encoder, so values which
+// originally contained a backslash followed by n, r or t can't be recovered.
+func parseLTSV(line string) map[string]any {
+	fields := map[string]any{}
+	for _, label := range strings.Split(line, "\t") {
+		key, val, ok := strings.Cut(label, ":")
+		if !ok {
+			continue
+		}
+		fields[key] = ltsvUnescaper.Replace(val)
+	}
+	return fields
+}
+
+// parseLogfmt parses a line of logfmt. Keys without a value have an empty value.
+func parseLogfmt(line string) (map[string]any, error) {
+	fields := map[string]any{}
+	for i := 0; i < len(line); {
+		// skip spaces between pairs.
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' {
+			i++
+		}
+		key := line[start:i]
+		if i == len(line) || line[i] == ' ' {
+			fields[key] = ""
+			continue
+		}
+		// skip the equals sign.
+		i++
+
+		if i < len(line) && line[i] == '"' {
+			val, n, err := unquotePrefix(line[i:])
+			if err != nil {
+				return nil, fmt.Errorf("value of %q: %w", key, err)
+			}
+			fields[key] = val
+			i += n
+			continue
+		}
+
+		start = i
+		for i < len(line) && line[i] != ' ' {
+			i++
+		}
+		fields[key] = line[start:i]
+	}
+	return fields, nil
+}
+
+// unquotePrefix unquotes the quoted string at the start of s,
+// returning the string and the number of bytes it used.
+func unquotePrefix(s string) (string, int, error) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			// skip the escaped character.
+			i++
+		case '"':
+			val, err := strconv.Unquote(s[:i+1])
+			return val, i + 1, err
+		}
+	}
+	return "", 0, errors.New("unterminated quoted string")
+}
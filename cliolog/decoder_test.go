@@ -0,0 +1,126 @@
+package cliolog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestDecoderRoundTrip(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:      zapcore.ErrorLevel,
+		Time:       time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "aws.sso",
+		Message:    "failed to \"refresh\" token\nplease retry",
+		Stack:      "main.run\n\t/src/main.go:12",
+	}
+	fields := []zapcore.Field{
+		zap.Int("count", 2),
+		zap.Bool("ok", false),
+		zap.String("path", `C:\Users\me`),
+		zap.String("note", "tab\there"),
+		zap.String("empty", ""),
+	}
+	// values are compared as strings, as LTSV and logfmt don't have types.
+	wantFields := map[string]string{
+		"count": "2",
+		"ok":    "false",
+		"path":  `C:\Users\me`,
+		"note":  "tab\there",
+		"empty": "",
+	}
+
+	tests := []struct {
+		format Format
+		enc    zapcore.Encoder
+	}{
+		{format: FormatJSON, enc: zapcore.NewJSONEncoder(machineEncoderConfig())},
+		{format: FormatLTSV, enc: NewLTSVEncoder(machineEncoderConfig())},
+		{format: FormatLogfmt, enc: NewLogfmtEncoder(machineEncoderConfig())},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var log bytes.Buffer
+			for i := 0; i < 2; i++ {
+				buf, err := tt.enc.EncodeEntry(ent, fields)
+				if err != nil {
+					t.Fatal(err)
+				}
+				log.Write(buf.Bytes())
+				// blank lines between entries are skipped.
+				log.WriteString("\n")
+			}
+
+			entries, err := NewDecoder(&log, tt.format).DecodeAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("decoded %d entries, want 2", len(entries))
+			}
+
+			got := entries[0]
+			if !got.Time.Equal(ent.Time) {
+				t.Errorf("Time = %s, want %s", got.Time, ent.Time)
+			}
+			if got.Level != ent.Level {
+				t.Errorf("Level = %s, want %s", got.Level, ent.Level)
+			}
+			if got.LoggerName != ent.LoggerName {
+				t.Errorf("LoggerName = %q, want %q", got.LoggerName, ent.LoggerName)
+			}
+			if got.Message != ent.Message {
+				t.Errorf("Message = %q, want %q", got.Message, ent.Message)
+			}
+			if got.Stack != ent.Stack {
+				t.Errorf("Stack = %q, want %q", got.Stack, ent.Stack)
+			}
+			if len(got.Fields) != len(wantFields) {
+				t.Errorf("Fields = %v, want %v", got.Fields, wantFields)
+			}
+			for k, want := range wantFields {
+				if v := fmt.Sprint(got.Fields[k]); v != want {
+					t.Errorf("Fields[%q] = %q, want %q", k, v, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecoderTrace(t *testing.T) {
+	log := `{"level":"trace","ts":"2023-01-02T03:04:05Z","msg":"my message"}`
+
+	ent, err := NewDecoder(strings.NewReader(log), FormatJSON).Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ent.Level != TraceLevel {
+		t.Errorf("Level = %s, want trace", ent.Level)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := []struct {
+		format Format
+		give   string
+	}{
+		{format: FormatJSON, give: `{"msg":`},
+		{format: FormatLogfmt, give: `msg="unterminated`},
+		{format: FormatLTSV, give: "level:nope\tmsg:bad level"},
+		{format: "yaml", give: "msg: unsupported"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			_, err := NewDecoder(strings.NewReader(tt.give), tt.format).Decode()
+			if err == nil || err == io.EOF {
+				t.Errorf("Decode() error = %v, want an error", err)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package cliolog
 
 import (
 	"encoding/hex"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/common-fate/clio/ansi"
 	"go.uber.org/zap"
@@ -33,6 +36,7 @@ func levelToANSIColor(l zapcore.Level) string {
 }
 
 var levelToSymbol = map[zapcore.Level]string{
+	TraceLevel:          "TRACE",
 	zapcore.DebugLevel:  "DEBUG",
 	zapcore.InfoLevel:   "i",
 	zapcore.WarnLevel:   "!",
@@ -49,6 +53,7 @@ var levelToSymbol = map[zapcore.Level]string{
 // ERROR: [✘]
 // WARN: [!]
 // DEBUG: [DEBUG]
+// TRACE: [TRACE]
 func SymbolLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	if s, ok := levelToSymbol[l]; ok {
 		enc.AppendString("[" + s + "]")
@@ -58,6 +63,17 @@ func SymbolLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 type consoleEncoder struct {
 	*ltsvEncoder
 	noColor *bool
+	// kind is the message kind set on the logger with With(), if any.
+	kind string
+	// pretty renders nested objects and arrays as a tree beneath the message.
+	pretty bool
+	// tree are the nested objects and arrays added to the logger with With()
+	// in pretty mode, which are rendered beneath the message.
+	tree []zapcore.Field
+	// align, if set, pads messages so that fields line up across messages.
+	align *aligner
+	// width, if set, returns the width of the terminal which messages are wrapped at.
+	width func() int
 }
 
 // NewConsoleEncoder creates an encoder whose output is designed for human -
@@ -83,9 +99,88 @@ func (c *consoleEncoder) Clone() zapcore.Encoder {
 	return &consoleEncoder{
 		ltsvEncoder: c.ltsvEncoder.Clone().(*ltsvEncoder),
 		noColor:     c.noColor,
+		kind:        c.kind,
+		pretty:      c.pretty,
+		tree:        c.tree[:len(c.tree):len(c.tree)],
+		align:       c.align,
+		width:       c.width,
 	}
 }
 
+// AddString implements zapcore.ObjectEncoder.
+// The kind of a message is displayed as a symbol rather than a field,
+// so registered kinds added to the logger with With() are captured here
+// rather than being encoded into the context.
+func (c *consoleEncoder) AddString(key, val string) {
+	if key == KindKey && c.nestingLevel == 0 && c.fieldNamePrefix == "" {
+		if _, ok := LookupKind(val); ok {
+			c.kind = val
+			return
+		}
+	}
+	c.ltsvEncoder.AddString(key, val)
+}
+
+// AddObject implements zapcore.ObjectEncoder.
+// In pretty mode, objects added to the logger with With() are rendered
+// beneath the message rather than being encoded into the context.
+func (c *consoleEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	if c.pretty && c.nestingLevel == 0 {
+		c.tree = append(c.tree, zap.Object(c.prefixedKey(key), obj))
+		return nil
+	}
+	return c.ltsvEncoder.AddObject(key, obj)
+}
+
+// AddArray implements zapcore.ObjectEncoder.
+// In pretty mode, arrays added to the logger with With() are rendered
+// beneath the message rather than being encoded into the context.
+func (c *consoleEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	if c.pretty && c.nestingLevel == 0 {
+		c.tree = append(c.tree, zap.Array(c.prefixedKey(key), arr))
+		return nil
+	}
+	return c.ltsvEncoder.AddArray(key, arr)
+}
+
+// prefixedKey returns key within the current namespace.
+func (c *consoleEncoder) prefixedKey(key string) string {
+	if c.fieldNamePrefix == "" {
+		return key
+	}
+	return c.fieldNamePrefix + "." + key
+}
+
+// splitTree separates the nested objects and arrays in fields, which are
+// rendered beneath the message in pretty mode, from the inline fields.
+// Keys of nested fields include the namespace they were added in.
+func (c *consoleEncoder) splitTree(fields []zapcore.Field) (inline, tree []zapcore.Field) {
+	if !c.pretty {
+		return fields, nil
+	}
+	prefix := c.fieldNamePrefix
+	inline = make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		switch {
+		case f.Type == zapcore.NamespaceType:
+			if prefix == "" {
+				prefix = f.Key
+			} else {
+				prefix = prefix + "." + f.Key
+			}
+			inline = append(inline, f)
+		case isNested(f):
+			if prefix != "" {
+				f.Key = prefix + "." + f.Key
+			}
+			tree = append(tree, f)
+		default:
+			inline = append(inline, f)
+		}
+	}
+	return inline, tree
+}
+
 // dim is the color used for context keys, time, and caller information
 var dim = ansi.ColorCode("240")
 
@@ -99,8 +194,12 @@ func (c *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field)
 
 	if c.TimeKey != "" {
 		c.colorDim(final.buf)
+		timeStart := final.buf.Len()
 		final.skipNextElementSeparator = true
 		c.EncodeTime(ent.Time, &final)
+		if final.buf.Len() > timeStart {
+			final.buf.AppendString(" ")
+		}
 	}
 
 	// color the level symbol and log message based on the level.
@@ -108,35 +207,52 @@ func (c *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field)
 
 	final.skipNextElementSeparator = c.LevelKey != ""
 
+	// the kind of the message, if any, is shown instead of the log level.
+	kind, hasKind := c.entryKind(ent, fields)
+	if hasKind {
+		fields = withoutKindField(fields)
+	}
+
 	// if the logger name matches NoPrefixName, we don't print a log level prefix
 	// or color the output.
-	if c.LevelKey != "" && ent.LoggerName != NoPrefixName {
-		// zap doesn't have a 'success' level, nor does it have an easy way to define
-		// custom logging levels.
-		//
-		// To get around this, we use a special named logger called 'clio.success'.
-		// emitting logs to this logger will cause messages to appear in green with a
-		// [✔] symbol as the logging level.
-		if ent.LoggerName == SuccessName {
-			c.applyColor(final.buf, ansi.ColorCode("green"))
-			final.buf.AppendString("[✔]")
+	if c.LevelKey != "" && !hasName(ent.LoggerName, NoPrefixName) {
+		if hasKind {
+			c.applyColor(final.buf, kind.Color)
+			final.buf.AppendString("[" + kind.Symbol + "]")
 		} else {
 			c.EncodeLevel(ent.Level, &final)
 		}
 	}
 
-	if final.buf.Len() > origLen && ent.LoggerName != NoPrefixName {
+	if final.buf.Len() > origLen && !hasName(ent.LoggerName, NoPrefixName) {
 		final.buf.AppendString(" ")
 	} else {
 		final.buf.Reset()
 	}
 
+	// continuation lines of wrapped messages are indented to the start of the message.
+	indent := lineWidth(final.buf.Bytes())
+
+	// nested objects and arrays are rendered beneath the message in pretty mode.
+	fields, tree := c.splitTree(fields)
+	tree = append(tree, c.tree...)
+
 	// Add the message itself.
 	if c.MessageKey != "" {
 		final.safeAddString(ent.Message, false)
+		// show the caller, if set, as a dimmed file.go:123 after the message.
+		if ent.Caller.Defined && c.CallerKey != "" && !hasName(ent.LoggerName, NoPrefixName) {
+			c.colorDim(final.buf)
+			final.buf.AppendString(" " + filepath.Base(ent.Caller.File) + ":" + strconv.Itoa(ent.Caller.Line))
+		}
 		// ensure a minimum of 2 spaces between the message and the fields,
-		// to improve readability
-		if len(fields) > 0 {
+		// to improve readability. Fields added to the logger with With are
+		// separated in the same way, even if the message has no fields.
+		if len(fields) > 0 || context.Len() > 0 {
+			if c.align != nil {
+				width := lineWidth(final.buf.Bytes())
+				final.buf.AppendString(strings.Repeat(" ", c.align.column(width, ent.Time)-width))
+			}
 			final.buf.AppendString("  ")
 		}
 	}
@@ -154,11 +270,31 @@ func (c *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field)
 		_, _ = final.buf.Write(context.Bytes())
 	}
 
+	// the message and inline fields are wrapped at the width of the terminal.
+	// The tree and stack trace are indented themselves, so they aren't wrapped.
+	// Output messages are written as-is, as they may be piped or copied.
+	if c.width != nil && !hasName(ent.LoggerName, NoPrefixName) {
+		if width := c.width(); width > 0 && indent < width/2 {
+			wrapped := wrap(final.buf.Bytes(), width, indent)
+			final.buf.Reset()
+			_, _ = final.buf.Write(wrapped)
+		}
+	}
+
+	if len(tree) > 0 {
+		appendTree(final.buf, tree)
+	}
+
 	// If there's no stacktrace key, honor that; this allows users to force
 	// single-line output.
+	// The stack trace is trimmed to the frames outside of clio and zap.
 	if ent.Stack != "" && c.StacktraceKey != "" {
-		final.buf.AppendByte('\n')
-		final.buf.AppendString(ent.Stack)
+		c.colorDim(final.buf)
+		frames := trimStack(ent.Stack)
+		module := stackModule(frames)
+		for _, f := range frames {
+			final.buf.AppendString("\n    at " + f.shortFunction() + " (" + f.location(module) + ")")
+		}
 	}
 	c.colorReset(final.buf)
 	final.buf.AppendByte('\n')
@@ -166,6 +302,29 @@ func (c *consoleEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field)
 	return final.buf, nil
 }
 
+// entryKind returns the registered kind of the entry. A kind field in the
+// entry's fields takes precedence over a kind set on the logger.
+func (c *consoleEncoder) entryKind(ent zapcore.Entry, fields []zapcore.Field) (Kind, bool) {
+	if k, ok := kindFromFields(fields); ok {
+		return k, true
+	}
+	return LookupKind(entryKind(ent, c.kind))
+}
+
+// withoutKindField returns fields with any registered kind fields removed.
+func withoutKindField(fields []zapcore.Field) []zapcore.Field {
+	out := make([]zapcore.Field, 0, len(fields))
+	for _, f := range fields {
+		if isKindField(f) {
+			if _, ok := LookupKind(f.String); ok {
+				continue
+			}
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
 func (c *consoleEncoder) colorDim(buf *buffer.Buffer) {
 	c.applyColor(buf, dim)
 }
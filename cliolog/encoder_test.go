@@ -0,0 +1,41 @@
+package cliolog
+
+import (
+	"bytes"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestConsoleEncoderFieldSeparator(t *testing.T) {
+	tests := []struct {
+		name   string
+		with   []zapcore.Field
+		fields []zapcore.Field
+		want   string
+	}{
+		{name: "no fields", want: "[i] my message\n"},
+		{name: "fields", fields: []zapcore.Field{zap.String("key", "value")}, want: "[i] my message  \tkey:value\n"},
+		// fields added with With are separated from the message in the same way.
+		{name: "context", with: []zapcore.Field{zap.String("key", "value")}, want: "[i] my message  \tkey:value\n"},
+		{
+			name:   "fields and context",
+			with:   []zapcore.Field{zap.String("profile", "dev")},
+			fields: []zapcore.Field{zap.String("key", "value")},
+			want:   "[i] my message  \tkey:value\tprofile:dev\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			noColor := true
+			log := New(zap.NewAtomicLevelAt(zap.InfoLevel), WithWriter(&b), WithNoColor(&noColor))
+			log.With(tt.with...).Info("my message", tt.fields...)
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
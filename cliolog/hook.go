@@ -0,0 +1,52 @@
+package cliolog
+
+import "go.uber.org/zap/zapcore"
+
+// Hook is called for each entry written by a logger created with New.
+// The fields include the fields added to the logger with With(), followed
+// by the fields of the entry itself.
+//
+// Hooks are called synchronously, so they should be fast and must not log.
+type Hook func(ent zapcore.Entry, fields []zapcore.Field)
+
+// WithHook adds a hook which is called for each entry written by the logger,
+// to the console or to the file log.
+func WithHook(h Hook) Option {
+	return func(o *Options) {
+		o.Hooks = append(o.Hooks, h)
+	}
+}
+
+// hookCore calls hooks for each entry written by the wrapped core.
+type hookCore struct {
+	zapcore.Core
+	hooks []Hook
+	// context are the fields added with With().
+	context []zapcore.Field
+}
+
+// With implements zapcore.Core
+func (c *hookCore) With(fields []zapcore.Field) zapcore.Core {
+	return &hookCore{
+		Core:    c.Core.With(fields),
+		hooks:   c.hooks,
+		context: append(c.context[:len(c.context):len(c.context)], fields...),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *hookCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checkWrapped(c.Core, ent, ce, c.callHooks)
+}
+
+// callHooks calls the hooks for an entry which is being written.
+func (c *hookCore) callHooks(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	all := fields
+	if len(c.context) > 0 {
+		all = append(append(make([]zapcore.Field, 0, len(c.context)+len(fields)), c.context...), fields...)
+	}
+	for _, h := range c.hooks {
+		h(ent, all)
+	}
+	return ent, fields
+}
@@ -0,0 +1,124 @@
+package cliolog
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/common-fate/clio/ansi"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// KindKey is the field key used to record the kind of a message,
+// such as "success". In JSON and LTSV output the kind is written
+// as a regular field, e.g. kind:success.
+const KindKey = "kind"
+
+// SuccessKind is the built-in kind used by clio.Success, which
+// prints messages in green with a [✔] symbol.
+const SuccessKind = "success"
+
+// FatalKind is the built-in kind used by clio.Fatal, which prints messages
+// in the same way as errors. It marks the message as fatal in the file log.
+const FatalKind = "fatal"
+
+// Kind is a user-definable message kind. The console encoder prints
+// messages of a registered kind with the kind's symbol and color
+// rather than the symbol and color of their log level.
+type Kind struct {
+	// Name of the kind, which is written to the KindKey field.
+	Name string
+	// Symbol is printed in place of the log level, e.g. "✔" is printed as [✔].
+	Symbol string
+	// Color is the ANSI escape sequence used for the symbol and message,
+	// e.g. ansi.Green or ansi.ColorCode("cyan+b").
+	Color string
+	// Level is the log level that messages of this kind are logged at.
+	Level zapcore.Level
+
+	enabled *atomic.Bool
+}
+
+// Field returns a zap field which marks a message as being of this kind.
+func (k Kind) Field() zap.Field {
+	return KindField(k.Name)
+}
+
+// Enabled returns true if messages of this kind are printed to the console.
+func (k Kind) Enabled() bool {
+	return k.enabled == nil || k.enabled.Load()
+}
+
+var (
+	kindsMu sync.RWMutex
+	kinds   = map[string]Kind{}
+)
+
+//nolint:gochecknoinits
+func init() {
+	RegisterKind(SuccessKind, "✔", ansi.Green, zapcore.InfoLevel)
+	RegisterKind(FatalKind, "✘", ansi.Red, zapcore.ErrorLevel)
+}
+
+// RegisterKind registers a message kind, such as "tip", "notice" or
+// "deprecated". Registering a kind with the same name as an existing
+// kind replaces it.
+//
+// Example:
+//
+//	cliolog.RegisterKind("tip", "💡", ansi.Cyan, zapcore.InfoLevel)
+func RegisterKind(name, symbol, color string, level zapcore.Level) Kind {
+	enabled := &atomic.Bool{}
+	enabled.Store(true)
+
+	k := Kind{
+		Name:    name,
+		Symbol:  symbol,
+		Color:   color,
+		Level:   level,
+		enabled: enabled,
+	}
+
+	kindsMu.Lock()
+	kinds[name] = k
+	kindsMu.Unlock()
+
+	return k
+}
+
+// LookupKind returns the kind registered with the provided name.
+func LookupKind(name string) (Kind, bool) {
+	kindsMu.RLock()
+	k, ok := kinds[name]
+	kindsMu.RUnlock()
+	return k, ok
+}
+
+// SetKindEnabled enables or disables printing messages of a kind to the console,
+// independently of the log level. Messages of a disabled kind are still written
+// to the file log, if one is configured.
+// Does nothing if the kind hasn't been registered.
+func SetKindEnabled(name string, enabled bool) {
+	if k, ok := LookupKind(name); ok {
+		k.enabled.Store(enabled)
+	}
+}
+
+// KindField returns a zap field which marks a message as being of the provided kind.
+func KindField(name string) zap.Field {
+	return zap.String(KindKey, name)
+}
+
+// kindFromFields returns the kind set in the provided fields, if any.
+func kindFromFields(fields []zapcore.Field) (Kind, bool) {
+	for _, f := range fields {
+		if isKindField(f) {
+			return LookupKind(f.String)
+		}
+	}
+	return Kind{}, false
+}
+
+func isKindField(f zapcore.Field) bool {
+	return f.Key == KindKey && f.Type == zapcore.StringType
+}
@@ -0,0 +1,33 @@
+package cliolog
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TraceLevel logs are more verbose than debug logs, and are used for
+// messages such as wire-level dumps which are too noisy to show
+// when debugging normally.
+const TraceLevel = zapcore.DebugLevel - 1
+
+// ParseLevel parses a level based on the lower-case or all-caps ASCII
+// representation of the log level. In addition to the levels supported by
+// zapcore.ParseLevel, it supports 'trace'.
+func ParseLevel(text string) (zapcore.Level, error) {
+	if strings.EqualFold(text, "trace") {
+		return TraceLevel, nil
+	}
+	return zapcore.ParseLevel(text)
+}
+
+// LowercaseLevelEncoder serializes a Level to a lowercase string.
+// It's the same as zapcore.LowercaseLevelEncoder but also supports
+// the trace level.
+func LowercaseLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	if l == TraceLevel {
+		enc.AppendString("trace")
+		return
+	}
+	zapcore.LowercaseLevelEncoder(l, enc)
+}
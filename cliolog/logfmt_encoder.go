@@ -0,0 +1,465 @@
+package cliolog
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+//nolint:gochecknoinits
+func init() {
+	_ = zap.RegisterEncoder("clio-logfmt", func(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+		return NewLogfmtEncoder(cfg), nil
+	})
+}
+
+// logfmtEncoder encodes entries in logfmt, such as:
+//
+//	level=info msg="my message" key=value
+type logfmtEncoder struct {
+	*zapcore.EncoderConfig
+	buf *buffer.Buffer
+	// fieldNamePrefix is prepended to keys, for namespaces and nested objects.
+	fieldNamePrefix string
+	// inArray is true if the encoder is encoding the elements of an array.
+	inArray bool
+	// elements is the number of elements encoded in the array.
+	elements int
+}
+
+// NewLogfmtEncoder creates an encoder which serializes entries in logfmt.
+// Values are quoted if they contain spaces, quotes, equals signs or control
+// characters. Nested objects are flattened with dotted keys, such as
+// http.status=500, in the same way as namespaces. Arrays are encoded as a
+// single value, such as ids=[1,2,3].
+//
+// It's registered with zap as "clio-logfmt", and can be used for the console
+// and the file log with WithConsoleEncoder and WithFileEncoder.
+func NewLogfmtEncoder(cfg zapcore.EncoderConfig) zapcore.Encoder {
+	return &logfmtEncoder{EncoderConfig: &cfg, buf: bufPool.Get()}
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	enc.addKey(key)
+	return enc.AppendArray(arr)
+}
+
+// AddObject implements zapcore.ObjectEncoder.
+// The fields of the object are flattened with dotted keys.
+func (enc *logfmtEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	prefix := enc.fieldNamePrefix
+	enc.OpenNamespace(key)
+	err := obj.MarshalLogObject(enc)
+	enc.fieldNamePrefix = prefix
+	return err
+}
+
+// AddBinary implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddBinary(key string, val []byte) {
+	enc.AddString(key, base64.StdEncoding.EncodeToString(val))
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddByteString(key string, val []byte) {
+	enc.addKey(key)
+	enc.AppendByteString(val)
+}
+
+// AddBool implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddBool(key string, val bool) {
+	enc.addKey(key)
+	enc.AppendBool(val)
+}
+
+// AddComplex128 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddComplex128(key string, val complex128) {
+	enc.addKey(key)
+	enc.AppendComplex128(val)
+}
+
+// AddDuration implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddDuration(key string, val time.Duration) {
+	enc.addKey(key)
+	enc.AppendDuration(val)
+}
+
+// AddFloat64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddFloat64(key string, val float64) {
+	enc.addKey(key)
+	enc.AppendFloat64(val)
+}
+
+// AddInt64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt64(key string, val int64) {
+	enc.addKey(key)
+	enc.AppendInt64(val)
+}
+
+// AddReflected implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddReflected(key string, obj interface{}) error {
+	enc.addKey(key)
+	return enc.AppendReflected(obj)
+}
+
+// OpenNamespace implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) OpenNamespace(key string) {
+	if enc.fieldNamePrefix == "" {
+		enc.fieldNamePrefix = key
+		return
+	}
+	enc.fieldNamePrefix = enc.fieldNamePrefix + "." + key
+}
+
+// AddString implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddString(key, val string) {
+	enc.addKey(key)
+	enc.AppendString(val)
+}
+
+// AddTime implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddTime(key string, val time.Time) {
+	enc.addKey(key)
+	enc.AppendTime(val)
+}
+
+// AddUint64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint64(key string, val uint64) {
+	enc.addKey(key)
+	enc.AppendUint64(val)
+}
+
+// AppendArray implements zapcore.ArrayEncoder.
+// The array is encoded as a single value, such as [1,2,3].
+func (enc *logfmtEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	enc.addElementSeparator()
+	child := enc.child(true)
+	defer child.buf.Free()
+
+	child.buf.AppendByte('[')
+	err := arr.MarshalLogArray(child)
+	child.buf.AppendByte(']')
+	enc.appendNested(child.buf.String())
+	return err
+}
+
+// AppendObject implements zapcore.ArrayEncoder.
+// Objects in arrays are encoded as a single value, such as {id=1 name=a}.
+func (enc *logfmtEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	enc.addElementSeparator()
+	child := enc.child(false)
+	defer child.buf.Free()
+
+	child.buf.AppendByte('{')
+	err := obj.MarshalLogObject(child)
+	child.buf.AppendByte('}')
+	enc.appendNested(child.buf.String())
+	return err
+}
+
+// appendNested appends an encoded array or object. In arrays it's appended
+// as-is, and otherwise it's quoted if required.
+func (enc *logfmtEncoder) appendNested(val string) {
+	if enc.inArray {
+		enc.buf.AppendString(val)
+		return
+	}
+	enc.appendValue(val, false)
+}
+
+// AppendBool implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendBool(val bool) {
+	enc.addElementSeparator()
+	enc.buf.AppendBool(val)
+}
+
+// AppendByteString implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendByteString(val []byte) {
+	enc.AppendString(string(val))
+}
+
+// AppendComplex128 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendComplex128(val complex128) {
+	enc.addElementSeparator()
+	r, i := real(val), imag(val)
+	enc.buf.AppendFloat(r, 64)
+	enc.buf.AppendByte('+')
+	enc.buf.AppendFloat(i, 64)
+	enc.buf.AppendByte('i')
+}
+
+// AppendDuration implements zapcore.ArrayEncoder
+func (enc *logfmtEncoder) AppendDuration(val time.Duration) {
+	cur := enc.buf.Len()
+	if e := enc.EncodeDuration; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.buf.Len() {
+		// if the duration encoder didn't encode anything, use the
+		// duration's string representation.
+		enc.AppendString(val.String())
+	}
+}
+
+// AppendInt64 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendInt64(val int64) {
+	enc.addElementSeparator()
+	enc.buf.AppendInt(val)
+}
+
+// AppendReflected implements zapcore.ArrayEncoder
+func (enc *logfmtEncoder) AppendReflected(val interface{}) error {
+	enc.AppendString(fmt.Sprintf("%+v", val))
+	return nil
+}
+
+// AppendString implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendString(val string) {
+	enc.addElementSeparator()
+	enc.appendValue(val, enc.inArray)
+}
+
+// AppendTime implements zapcore.ArrayEncoder
+func (enc *logfmtEncoder) AppendTime(val time.Time) {
+	cur := enc.buf.Len()
+	if e := enc.EncodeTime; e != nil {
+		e(val, enc)
+	}
+	if cur == enc.buf.Len() {
+		// if the time encoder didn't encode anything, use RFC3339.
+		enc.AppendString(val.Format(time.RFC3339Nano))
+	}
+}
+
+// AppendUint64 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUint64(val uint64) {
+	enc.addElementSeparator()
+	enc.buf.AppendUint(val)
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddComplex64(k string, v complex64) { enc.AddComplex128(k, complex128(v)) }
+
+// AddFloat32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddFloat32(k string, v float32) { enc.AddFloat64(k, float64(v)) }
+
+// AddInt implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt(k string, v int) { enc.AddInt64(k, int64(v)) }
+
+// AddInt32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt32(k string, v int32) { enc.AddInt64(k, int64(v)) }
+
+// AddInt16 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt16(k string, v int16) { enc.AddInt64(k, int64(v)) }
+
+// AddInt8 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddInt8(k string, v int8) { enc.AddInt64(k, int64(v)) }
+
+// AddUint implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint(k string, v uint) { enc.AddUint64(k, uint64(v)) }
+
+// AddUint32 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint32(k string, v uint32) { enc.AddUint64(k, uint64(v)) }
+
+// AddUint16 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint16(k string, v uint16) { enc.AddUint64(k, uint64(v)) }
+
+// AddUint8 implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUint8(k string, v uint8) { enc.AddUint64(k, uint64(v)) }
+
+// AddUintptr implements zapcore.ObjectEncoder
+func (enc *logfmtEncoder) AddUintptr(k string, v uintptr) { enc.AddUint64(k, uint64(v)) }
+
+// AppendComplex64 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendComplex64(v complex64) { enc.AppendComplex128(complex128(v)) }
+
+// AppendFloat64 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendFloat64(v float64) { enc.appendFloat(v, 64) }
+
+// AppendFloat32 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendFloat32(v float32) { enc.appendFloat(float64(v), 32) }
+
+// AppendInt implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendInt(v int) { enc.AppendInt64(int64(v)) }
+
+// AppendInt32 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendInt32(v int32) { enc.AppendInt64(int64(v)) }
+
+// AppendInt16 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendInt16(v int16) { enc.AppendInt64(int64(v)) }
+
+// AppendInt8 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendInt8(v int8) { enc.AppendInt64(int64(v)) }
+
+// AppendUint implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUint(v uint) { enc.AppendUint64(uint64(v)) }
+
+// AppendUint32 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUint32(v uint32) { enc.AppendUint64(uint64(v)) }
+
+// AppendUint16 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUint16(v uint16) { enc.AppendUint64(uint64(v)) }
+
+// AppendUint8 implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUint8(v uint8) { enc.AppendUint64(uint64(v)) }
+
+// AppendUintptr implements zapcore.PrimitiveArrayEncoder
+func (enc *logfmtEncoder) AppendUintptr(v uintptr) { enc.AppendUint64(uint64(v)) }
+
+// Clone implements zapcore.Encoder
+func (enc *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := *enc
+	clone.buf = bufPool.Get()
+	_, _ = clone.buf.Write(enc.buf.Bytes())
+	return &clone
+}
+
+// EncodeEntry implements zapcore.Encoder
+func (enc *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	final := *enc
+	final.buf = bufPool.Get()
+	// the namespace only applies to the context and fields.
+	final.fieldNamePrefix = ""
+
+	if final.TimeKey != "" {
+		final.AddTime(final.TimeKey, ent.Time)
+	}
+	if final.LevelKey != "" {
+		final.addKey(final.LevelKey)
+		if final.EncodeLevel != nil {
+			final.EncodeLevel(ent.Level, &final)
+		} else {
+			final.AppendString(ent.Level.String())
+		}
+	}
+	if ent.LoggerName != "" && final.NameKey != "" {
+		final.AddString(final.NameKey, ent.LoggerName)
+	}
+	if ent.Caller.Defined && final.CallerKey != "" {
+		final.addKey(final.CallerKey)
+		if final.EncodeCaller != nil {
+			final.EncodeCaller(ent.Caller, &final)
+		} else {
+			final.AppendString(ent.Caller.TrimmedPath())
+		}
+	}
+	if final.MessageKey != "" {
+		final.AddString(final.MessageKey, ent.Message)
+	}
+	if enc.buf.Len() > 0 {
+		if final.buf.Len() > 0 {
+			final.buf.AppendByte(' ')
+		}
+		_, _ = final.buf.Write(enc.buf.Bytes())
+	}
+	final.fieldNamePrefix = enc.fieldNamePrefix
+	for i := range fields {
+		fields[i].AddTo(&final)
+	}
+	final.fieldNamePrefix = ""
+	if ent.Stack != "" && final.StacktraceKey != "" {
+		final.AddString(final.StacktraceKey, ent.Stack)
+	}
+	final.buf.AppendByte('\n')
+	return final.buf, nil
+}
+
+// child returns an encoder which encodes into a separate buffer,
+// for arrays and objects nested in arrays.
+func (enc *logfmtEncoder) child(inArray bool) *logfmtEncoder {
+	return &logfmtEncoder{EncoderConfig: enc.EncoderConfig, buf: bufPool.Get(), inArray: inArray}
+}
+
+// addKey adds a key, including the namespace prefix, followed by an equals sign.
+// Characters which aren't allowed in keys are replaced with underscores.
+func (enc *logfmtEncoder) addKey(key string) {
+	if enc.buf.Len() > 0 && !enc.afterOpenBrace() {
+		enc.buf.AppendByte(' ')
+	}
+	if enc.fieldNamePrefix != "" {
+		enc.safeAddKey(enc.fieldNamePrefix)
+		enc.buf.AppendByte('.')
+	}
+	if key == "" {
+		key = "_"
+	}
+	enc.safeAddKey(key)
+	enc.buf.AppendByte('=')
+}
+
+// afterOpenBrace returns true if the last byte in the buffer opens an object.
+func (enc *logfmtEncoder) afterOpenBrace() bool {
+	b := enc.buf.Bytes()
+	return len(b) > 0 && b[len(b)-1] == '{'
+}
+
+// addElementSeparator adds a comma between the elements of an array.
+func (enc *logfmtEncoder) addElementSeparator() {
+	if !enc.inArray {
+		return
+	}
+	if enc.elements > 0 {
+		enc.buf.AppendByte(',')
+	}
+	enc.elements++
+}
+
+// safeAddKey appends a key, replacing characters which aren't allowed in keys with underscores.
+func (enc *logfmtEncoder) safeAddKey(key string) {
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || unicode.IsControl(r) {
+			enc.buf.AppendByte('_')
+			continue
+		}
+		enc.buf.AppendString(string(r))
+	}
+}
+
+// appendValue appends a value, quoting it if required. Values in arrays are
+// also quoted if they contain commas or brackets.
+func (enc *logfmtEncoder) appendValue(val string, inArray bool) {
+	if needsQuotes(val, inArray) {
+		enc.buf.AppendString(strconv.Quote(val))
+		return
+	}
+	enc.buf.AppendString(val)
+}
+
+// needsQuotes returns true if a logfmt value must be quoted.
+func needsQuotes(val string, inArray bool) bool {
+	if val == "" {
+		return true
+	}
+	for _, r := range val {
+		switch {
+		case r <= ' ', r == '=', r == '"', r == '\\', r == utf8.RuneError, unicode.IsControl(r):
+			return true
+		case inArray && strings.ContainsRune(",[]{}", r):
+			return true
+		}
+	}
+	return false
+}
+
+func (enc *logfmtEncoder) appendFloat(val float64, bitSize int) {
+	enc.addElementSeparator()
+	switch {
+	case math.IsNaN(val):
+		enc.buf.AppendString("NaN")
+	case math.IsInf(val, 1):
+		enc.buf.AppendString("+Inf")
+	case math.IsInf(val, -1):
+		enc.buf.AppendString("-Inf")
+	default:
+		enc.buf.AppendFloat(val, bitSize)
+	}
+}
@@ -0,0 +1,126 @@
+package cliolog
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// testObject is an object with nested fields, for testing encoders.
+type testObject struct {
+	ID   int
+	Name string
+}
+
+func (o testObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("id", o.ID)
+	enc.AddString("name", o.Name)
+	return nil
+}
+
+func TestLogfmtEncoder(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:      zapcore.WarnLevel,
+		Time:       time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		LoggerName: "aws",
+		Message:    `retrying "request"`,
+	}
+
+	tests := []struct {
+		name   string
+		fields []zapcore.Field
+		want   string
+	}{
+		{
+			name: "no fields",
+			want: `ts=2023-01-02T03:04:05Z level=warn logger=aws msg="retrying \"request\""`,
+		},
+		{
+			name: "quoting",
+			fields: []zapcore.Field{
+				zap.String("plain", "value"),
+				zap.String("spaces", "a value"),
+				zap.String("empty", ""),
+				zap.String("equals", "a=b"),
+				zap.String("newline", "a\nb"),
+				zap.String("bad key", "value"),
+			},
+			want: `ts=2023-01-02T03:04:05Z level=warn logger=aws msg="retrying \"request\"" plain=value spaces="a value" empty="" equals="a=b" newline="a\nb" bad_key=value`,
+		},
+		{
+			name: "types",
+			fields: []zapcore.Field{
+				zap.Int("int", 1),
+				zap.Bool("bool", true),
+				zap.Float64("float", 1.5),
+				zap.Duration("duration", time.Second),
+				zap.Error(errors.New("failed to connect")),
+			},
+			want: `ts=2023-01-02T03:04:05Z level=warn logger=aws msg="retrying \"request\"" int=1 bool=true float=1.5 duration=1 error="failed to connect"`,
+		},
+		{
+			name: "nested objects are flattened",
+			fields: []zapcore.Field{
+				zap.Object("user", testObject{ID: 1, Name: "alice"}),
+				zap.Namespace("http"),
+				zap.Int("status", 500),
+			},
+			want: `ts=2023-01-02T03:04:05Z level=warn logger=aws msg="retrying \"request\"" user.id=1 user.name=alice http.status=500`,
+		},
+		{
+			name: "arrays",
+			fields: []zapcore.Field{
+				zap.Ints("ids", []int{1, 2, 3}),
+				zap.Strings("names", []string{"a", "b c"}),
+				zap.Objects("users", []testObject{{ID: 1, Name: "alice"}}),
+			},
+			want: `ts=2023-01-02T03:04:05Z level=warn logger=aws msg="retrying \"request\"" ids=[1,2,3] names="[a,\"b c\"]" users="[{id=1 name=alice}]"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewLogfmtEncoder(machineEncoderConfig())
+			buf, err := enc.EncodeEntry(ent, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("EncodeEntry() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogfmtEncoderContext(t *testing.T) {
+	var console, file bytes.Buffer
+	ws := zapcore.AddSync(&file)
+
+	logger := New(zap.NewAtomicLevel(),
+		WithWriter(&console),
+		WithConsoleEncoder(NewLogfmtEncoder),
+		WithFileEncoder(NewLogfmtEncoder),
+		func(o *Options) { o.FileWriteSyncer = &ws },
+	)
+
+	logger.With(zap.String("profile", "dev")).Info("my message", zap.Int("count", 2))
+
+	for name, got := range map[string]string{"console": console.String(), "file": file.String()} {
+		if !strings.HasSuffix(got, ` level=info msg="my message" profile=dev count=2`+"\n") {
+			t.Errorf("%s output = %q, want logfmt", name, got)
+		}
+	}
+}
+
+func TestLogfmtEncoderRegistered(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Encoding = "clio-logfmt"
+	cfg.OutputPaths = []string{"stderr"}
+	if _, err := cfg.Build(); err != nil {
+		t.Fatalf("building logger with clio-logfmt encoding: %s", err)
+	}
+}
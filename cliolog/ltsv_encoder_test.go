@@ -0,0 +1,77 @@
+package cliolog
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLTSVEncoder(t *testing.T) {
+	ent := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Time:    time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "my\tmessage",
+	}
+
+	tests := []struct {
+		name   string
+		opts   []LTSVOption
+		fields []zapcore.Field
+		want   string
+	}{
+		{
+			name:   "escaping",
+			fields: []zapcore.Field{zap.String("a:key", "line\nbreak"), zap.ByteString("bytes", []byte("raw"))},
+			want:   "level:info\tts:2023-01-02T03:04:05Z\tmsg:my\\tmessage\ta_key:line\\nbreak\tbytes:raw",
+		},
+		{
+			name:   "namespaces",
+			fields: []zapcore.Field{zap.Namespace("http"), zap.Int("status", 500)},
+			want:   "level:info\tts:2023-01-02T03:04:05Z\tmsg:my\\tmessage\thttp.status:500",
+		},
+		{
+			name:   "binary",
+			fields: []zapcore.Field{zap.Binary("data", []byte{1, 2})},
+			want:   "level:info\tts:2023-01-02T03:04:05Z\tmsg:my\\tmessage\tdata:AQI=",
+		},
+		{
+			name:   "binary encoder option",
+			opts:   []LTSVOption{LTSVBinaryEncoder(hex.EncodeToString)},
+			fields: []zapcore.Field{zap.Binary("data", []byte{1, 2})},
+			want:   "level:info\tts:2023-01-02T03:04:05Z\tmsg:my\\tmessage\tdata:0102",
+		},
+		{
+			name: "key escape option",
+			opts: []LTSVOption{LTSVEscapeKey(func(key string) string {
+				return strings.NewReplacer(".", "_", ":", "-").Replace(key)
+			})},
+			fields: []zapcore.Field{zap.Namespace("http"), zap.Int("a:status", 500)},
+			want:   "level:info\tts:2023-01-02T03:04:05Z\tmsg:my\\tmessage\thttp_a-status:500",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewLTSVEncoder(machineEncoderConfig(), tt.opts...)
+			buf, err := enc.EncodeEntry(ent, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(buf.String(), "\n"); got != tt.want {
+				t.Errorf("EncodeEntry() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLTSVEncoderRegistered(t *testing.T) {
+	cfg := zap.NewProductionConfig()
+	cfg.Encoding = "ltsv"
+	cfg.OutputPaths = []string{"stderr"}
+	if _, err := cfg.Build(); err != nil {
+		t.Fatalf("building logger with ltsv encoding: %s", err)
+	}
+}
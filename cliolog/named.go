@@ -1,12 +1,24 @@
 package cliolog
 
+import "strings"
+
 const (
 	// SuccessName is a designated logging name which prints
 	// messages with a [✔] symbol rather than their regular
 	// log level.
+	//
+	// Deprecated: add KindField(SuccessKind) to the message instead.
 	SuccessName = "clio.success"
 
 	// NoPrefixName is a designated logging name which prints
 	// messages without a prefix.
 	NoPrefixName = "clio.noprefix"
 )
+
+// hasName returns true if loggerName is the designated name, or if it
+// ends with it. zap joins the names of nested loggers with a '.', so a
+// logger created with S().Named("aws").Named(SuccessName) is
+// called "aws.clio.success".
+func hasName(loggerName, name string) bool {
+	return loggerName == name || strings.HasSuffix(loggerName, "."+name)
+}
@@ -0,0 +1,186 @@
+package cliolog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NamedLevels holds log levels for named loggers, such as those
+// created with S().Named("aws"). Loggers without a level of their
+// own use the default level.
+//
+// A level set for a name also applies to loggers nested beneath it,
+// so a level set for "aws" applies to a logger named "aws.sso".
+// The most specific name takes precedence.
+type NamedLevels struct {
+	// Default is the level used for unnamed loggers, and loggers
+	// without a level set for their name.
+	Default zap.AtomicLevel
+
+	mu     sync.RWMutex
+	levels map[string]zapcore.Level
+}
+
+// NewNamedLevels creates NamedLevels with the provided default level.
+func NewNamedLevels(def zap.AtomicLevel) *NamedLevels {
+	return &NamedLevels{
+		Default: def,
+		levels:  map[string]zapcore.Level{},
+	}
+}
+
+// Set sets the level for a logger name.
+func (n *NamedLevels) Set(name string, level zapcore.Level) {
+	n.mu.Lock()
+	n.levels[name] = level
+	n.mu.Unlock()
+}
+
+// Unset removes the level for a logger name, so that the default level is used.
+func (n *NamedLevels) Unset(name string) {
+	n.mu.Lock()
+	delete(n.levels, name)
+	n.mu.Unlock()
+}
+
+// Levels returns a copy of the levels set for logger names.
+func (n *NamedLevels) Levels() map[string]zapcore.Level {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	levels := make(map[string]zapcore.Level, len(n.levels))
+	for k, v := range n.levels {
+		levels[k] = v
+	}
+	return levels
+}
+
+// Replace replaces all of the levels set for logger names.
+// It does not change the default level.
+func (n *NamedLevels) Replace(levels map[string]zapcore.Level) {
+	cp := make(map[string]zapcore.Level, len(levels))
+	for k, v := range levels {
+		cp[k] = v
+	}
+	n.mu.Lock()
+	n.levels = cp
+	n.mu.Unlock()
+}
+
+// Enabled implements zapcore.LevelEnabler. It returns true if
+// the level is enabled for any logger name, or by the default level.
+func (n *NamedLevels) Enabled(level zapcore.Level) bool {
+	if n.Default.Enabled(level) {
+		return true
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, l := range n.levels {
+		if l.Enabled(level) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnabledFor returns true if the level is enabled for a logger with the provided name.
+func (n *NamedLevels) EnabledFor(name string, level zapcore.Level) bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	for name != "" {
+		if l, ok := n.levels[name]; ok {
+			return l.Enabled(level)
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return n.Default.Enabled(level)
+}
+
+// LevelSpec is a parsed log level specification, such as "info,aws=debug,keychain=warn".
+type LevelSpec struct {
+	// HasDefault is true if the spec contained a default level.
+	HasDefault bool
+	// Default is the level for unnamed loggers.
+	Default zapcore.Level
+	// Named are the levels for named loggers.
+	Named map[string]zapcore.Level
+}
+
+// ParseLevelSpec parses a comma-separated log level specification.
+// Each element is either a level, which sets the default level, or a
+// name=level pair, which sets the level for a named logger.
+// Levels are parsed with ParseLevel.
+//
+// Example:
+//
+//	ParseLevelSpec("info,aws=debug,keychain=warn")
+func ParseLevelSpec(spec string) (LevelSpec, error) {
+	ls := LevelSpec{Named: map[string]zapcore.Level{}}
+
+	if strings.TrimSpace(spec) == "" {
+		return LevelSpec{}, fmt.Errorf("empty log level spec")
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, level, named := strings.Cut(part, "=")
+		if !named {
+			lvl, err := ParseLevel(part)
+			if err != nil {
+				return LevelSpec{}, err
+			}
+			ls.HasDefault = true
+			ls.Default = lvl
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return LevelSpec{}, fmt.Errorf("missing logger name in log level spec %q", part)
+		}
+		lvl, err := ParseLevel(strings.TrimSpace(level))
+		if err != nil {
+			return LevelSpec{}, err
+		}
+		ls.Named[name] = lvl
+	}
+
+	return ls, nil
+}
+
+// namedLevelCore filters entries using the level for the logger's name.
+type namedLevelCore struct {
+	zapcore.Core
+	levels *NamedLevels
+}
+
+// Enabled implements zapcore.Core
+func (c *namedLevelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *namedLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &namedLevelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check implements zapcore.Core
+func (c *namedLevelCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levels.EnabledFor(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -2,67 +2,274 @@ package cliolog
 
 import (
 	"io"
+	"os"
 	"time"
 
-	"github.com/mattn/go-colorable"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// Option configures a logger created with New.
+type Option func(*Options)
+
+// Options configure a logger created with New.
 type Options struct {
-	Writer          io.Writer
+	// Writer is where status messages, such as Info, Warn and Error messages, are written.
+	Writer io.Writer
+	// OutputWriter is where messages from the NoPrefixName logger
+	// (printed by clio.Log) are written.
+	OutputWriter    io.Writer
 	NoColor         *bool
 	FileWriteSyncer *zapcore.WriteSyncer
+	// FileCloser closes the file log, if there is one.
+	// It's not called by the logger, and should be closed by the caller when the program exits.
+	FileCloser  io.Closer
+	NamedLevels *NamedLevels
+	// ExtraWriters are additional writers which receive the same
+	// messages as the console, without colors.
+	ExtraWriters []io.Writer
+	// Dedup collapses identical consecutive console messages.
+	Dedup bool
+	// Sampling, if set, samples debug and trace console messages.
+	Sampling *SamplingConfig
+	// Hooks are called for each entry written by the logger.
+	Hooks []Hook
+	// Recorder, if set, records the entries which aren't printed to the console
+	// and dumps them when an error is logged.
+	Recorder *Recorder
+	// Redactor, if set, redacts secrets from messages and fields.
+	Redactor *Redactor
+	// Caller controls when the caller of a message is shown on the console.
+	Caller CallerMode
+	// TimeFormat controls how the time of each message is shown on the console.
+	TimeFormat TimeFormat
+	// ErrorStacks captures stack traces for error messages when debug messages are enabled.
+	ErrorStacks bool
+	// Pretty renders nested objects and arrays as an indented tree beneath
+	// the message when writing to a terminal.
+	Pretty bool
+	// AlignFields pads messages so that the fields of consecutive messages line up.
+	AlignFields bool
+	// NoWrap disables wrapping messages at the width of the terminal.
+	NoWrap bool
+	// ConsoleEncoder, if set, creates the encoder for the console in place
+	// of the human-readable console encoder.
+	ConsoleEncoder func(zapcore.EncoderConfig) zapcore.Encoder
+	// FileEncoder, if set, creates the encoder for the file log in place of the JSON encoder.
+	FileEncoder func(zapcore.EncoderConfig) zapcore.Encoder
 }
 
 // New returns a CLI-friendly zap logger which prints to stderr by default.
-func New(level zap.AtomicLevel, opts ...func(*Options)) *zap.Logger {
+// Messages from the NoPrefixName logger are output rather than status messages,
+// and are printed to stdout by default so that they can be piped.
+func New(level zap.AtomicLevel, opts ...Option) *zap.Logger {
 	o := Options{
-		Writer: colorable.NewColorableStderr(),
+		Writer:       NewColorable(os.Stderr),
+		OutputWriter: NewColorable(os.Stdout),
 	}
 
 	for _, opt := range opts {
 		opt(&o)
 	}
 
+	// the console level is either the provided level, or per-name levels.
+	var consoleLevel zapcore.LevelEnabler = level
+	if o.NamedLevels != nil {
+		consoleLevel = o.NamedLevels
+	}
+
 	ec := zap.NewDevelopmentEncoderConfig()
 	ec.EncodeLevel = SymbolLevelEncoder
-	// no-op time encoder, by default.
-	ec.EncodeTime = func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {}
+	// the time isn't shown, by default.
+	ec.EncodeTime = timeEncoder(o.TimeFormat)
+
+	cores := []zapcore.Core{newConsoleCore(&ec, o, consoleLevel)}
 
 	// if fileWriteSyncer is present then write logs to file as well as showing to console.
+	var fileCore zapcore.Core
 	if o.FileWriteSyncer != nil {
-		fec := zap.NewProductionEncoderConfig()
-		fec.EncodeTime = zapcore.TimeEncoder(func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
-			pae.AppendString(t.UTC().Format("2006-01-02T15:04:05Z0700"))
-		})
-		fileEncoder := zapcore.NewJSONEncoder(fec)
+		newFileEncoder := zapcore.NewJSONEncoder
+		if o.FileEncoder != nil {
+			newFileEncoder = o.FileEncoder
+		}
+		fileEncoder := newFileEncoder(machineEncoderConfig())
 
 		// fileEncoder should have debug level irrespective of provided level.
-		core := zapcore.NewTee(zapcore.NewCore(fileEncoder, zapcore.AddSync(*o.FileWriteSyncer), zap.DebugLevel), zapcore.NewCore(NewConsoleEncoder(&ec, o.NoColor), zapcore.AddSync(o.Writer), level))
+		// Trace logs are only written if the provided level enables them.
+		fileLevel := zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l >= zapcore.DebugLevel || consoleLevel.Enabled(l)
+		})
+		fileCore = zapcore.NewCore(fileEncoder, zapcore.AddSync(*o.FileWriteSyncer), fileLevel)
+		cores = append(cores, fileCore)
+	}
+
+	core := zapcore.NewTee(cores...)
+
+	if len(o.Hooks) > 0 {
+		core = &hookCore{Core: core, hooks: o.Hooks}
+	}
+
+	// the recorder is teed first so that the recorded entries are dumped
+	// before an error, and outside of the hooks so that they aren't called
+	// for entries which are only recorded.
+	if o.Recorder != nil {
+		core = zapcore.NewTee(newRecorderCore(o.Recorder, &ec, o, consoleLevel, fileCore), core)
+	}
+
+	// secrets are redacted before any of the other cores see the entry.
+	if o.Redactor != nil {
+		core = &redactCore{Core: core, r: o.Redactor}
+	}
+
+	var zopts []zap.Option
+	if o.ErrorStacks {
+		core = newStackCore(core, o)
+		zopts = append(zopts, errorStacks(consoleLevel))
+	}
+
+	return zap.New(core, zopts...)
+}
+
+// machineEncoderConfig returns the encoder config for machine-readable output,
+// such as the file log.
+func machineEncoderConfig() zapcore.EncoderConfig {
+	ec := zap.NewProductionEncoderConfig()
+	ec.EncodeTime = zapcore.TimeEncoder(func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+		pae.AppendString(t.UTC().Format("2006-01-02T15:04:05Z0700"))
+	})
+	ec.EncodeLevel = LowercaseLevelEncoder
+	return ec
+}
+
+// alwaysNoColor is used to disable colors for extra writers.
+var alwaysNoColor = true
+
+// newConsoleCore returns the core which writes human-readable output to the console
+// and any extra writers. Status messages are written to o.Writer, and output messages
+// are written to o.OutputWriter.
+func newConsoleCore(ec *zapcore.EncoderConfig, o Options, level zapcore.LevelEnabler) zapcore.Core {
+	cores := []zapcore.Core{
+		&splitCore{
+			status: newWriterCore(ec, o, level, o.Writer),
+			output: newWriterCore(ec, o, level, o.OutputWriter),
+		},
+	}
+
+	// extra writers receive the console output without colors.
+	for _, w := range o.ExtraWriters {
+		extra := o
+		extra.NoColor = &alwaysNoColor
+		cores = append(cores, newWriterCore(ec, extra, level, w))
+	}
+
+	core := zapcore.NewTee(cores...)
+
+	if o.Sampling != nil {
+		core = newDebugSamplerCore(core, *o.Sampling)
+	}
+
+	// rate limits are applied across the console and extra writers,
+	// so that they print the same messages.
+	core = &rateLimitCore{Core: core}
+
+	if o.NamedLevels != nil {
+		core = &namedLevelCore{Core: core, levels: o.NamedLevels}
+	}
+
+	return newCallerCore(core, o, level)
+}
 
-		return zap.New(core)
+// newConsoleEncoder returns the encoder for the console. Custom console
+// encoders use the machine-readable encoder config, so that the level and
+// time can be parsed.
+func newConsoleEncoder(ec *zapcore.EncoderConfig, o Options, w io.Writer) zapcore.Encoder {
+	if o.ConsoleEncoder != nil {
+		return o.ConsoleEncoder(machineEncoderConfig())
 	}
+	enc := NewConsoleEncoder(ec, o.NoColor).(*consoleEncoder)
+	enc.pretty = o.Pretty && isTerminal(w)
+	if o.AlignFields {
+		enc.align = newAligner(w)
+	}
+	if !o.NoWrap && isTerminal(w) {
+		enc.width = func() int { return terminalWidth(w) }
+	}
+	return enc
+}
 
-	log := zap.New(zapcore.NewCore(
-		NewConsoleEncoder(&ec, o.NoColor),
-		zapcore.AddSync(o.Writer),
+// newWriterCore returns a core which writes human-readable output to w.
+func newWriterCore(ec *zapcore.EncoderConfig, o Options, level zapcore.LevelEnabler, w io.Writer) zapcore.Core {
+	core := zapcore.NewCore(
+		newConsoleEncoder(ec, o, w),
+		zapcore.AddSync(w),
 		level,
-	))
+	)
+
+	if o.Dedup {
+		core = newDedupCore(core)
+	}
 
-	return log
+	return newKindCore(core)
 }
 
 // WithWriter specifies an io.Writer to write logs to.
-func WithWriter(w io.Writer) func(*Options) {
+func WithWriter(w io.Writer) Option {
 	return func(o *Options) {
 		o.Writer = w
 	}
 }
 
+// WithOutputWriter specifies an io.Writer to write output messages to.
+// Output messages are those logged to the NoPrefixName logger, such as by clio.Log.
+func WithOutputWriter(w io.Writer) Option {
+	return func(o *Options) {
+		o.OutputWriter = w
+	}
+}
+
+// WithExtraWriter adds an io.Writer which receives the same messages as the
+// console, in the console format without colors. Both status and output messages
+// are written to it.
+func WithExtraWriter(w io.Writer) Option {
+	return func(o *Options) {
+		o.ExtraWriters = append(o.ExtraWriters, w)
+	}
+}
+
+// WithDedup collapses identical consecutive console messages. The first
+// message is printed, and when a different message is logged a summary
+// such as "retrying... (repeated 12 times)" is printed. Every message is
+// still written to the file log, if one is configured.
+//
+// The logger should be synced before exiting, so that the summary of any
+// trailing repeated messages is printed.
+func WithDedup() Option {
+	return func(o *Options) {
+		o.Dedup = true
+	}
+}
+
+// WithSampling samples debug and trace console messages, to avoid floods of
+// debug output. Messages at higher levels, and messages written to the file
+// log, are not sampled.
+func WithSampling(cfg SamplingConfig) Option {
+	return func(o *Options) {
+		o.Sampling = &cfg
+	}
+}
+
+// WithNamedLevels filters console output using per-name log levels.
+// The default level of the NamedLevels is used for unnamed loggers,
+// in place of the level provided to New.
+func WithNamedLevels(levels *NamedLevels) Option {
+	return func(o *Options) {
+		o.NamedLevels = levels
+	}
+}
+
 // WithNoColor sets up a colorization bypass.
-func WithNoColor(noColor *bool) func(*Options) {
+func WithNoColor(noColor *bool) Option {
 	return func(o *Options) {
 		o.NoColor = noColor
 	}
@@ -85,8 +292,26 @@ type FileLoggerConfig struct {
 	ShouldCompress bool
 }
 
+// WithConsoleEncoder replaces the human-readable console output with an encoder,
+// such as NewLogfmtEncoder or zapcore.NewJSONEncoder. The encoder is created
+// with a machine-readable config, with lowercase levels and UTC timestamps.
+func WithConsoleEncoder(newEncoder func(zapcore.EncoderConfig) zapcore.Encoder) Option {
+	return func(o *Options) {
+		o.ConsoleEncoder = newEncoder
+	}
+}
+
+// WithFileEncoder sets the encoder for the file log, such as NewLogfmtEncoder.
+// The default is zapcore.NewJSONEncoder.
+func WithFileEncoder(newEncoder func(zapcore.EncoderConfig) zapcore.Encoder) Option {
+	return func(o *Options) {
+		o.FileEncoder = newEncoder
+	}
+}
+
 // WithFileLogger will write logs to a file using lumberjack package in addition to printing it in console.
-func WithFileLogger(cfg FileLoggerConfig) func(*Options) {
+// The file is closed by Options.FileCloser.
+func WithFileLogger(cfg FileLoggerConfig) Option {
 	loggerCfg := lumberjack.Logger{
 		Filename:   cfg.Filename,
 		MaxSize:    1,
@@ -111,5 +336,6 @@ func WithFileLogger(cfg FileLoggerConfig) func(*Options) {
 
 	return func(o *Options) {
 		o.FileWriteSyncer = &ws
+		o.FileCloser = &loggerCfg
 	}
 }
@@ -0,0 +1,216 @@
+package cliolog
+
+import (
+	"sync"
+
+	"github.com/common-fate/clio/ansi"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RecorderConfig configures a Recorder.
+type RecorderConfig struct {
+	// Size is the number of entries which are kept.
+	// If set to 0 the default is 100.
+	Size int
+	// Console dumps the entries to the console under a "recent debug output" header.
+	Console bool
+	// File dumps the entries to the file log, if one is configured.
+	// Only entries which weren't already written to the file log are dumped.
+	File bool
+}
+
+// Recorder is a flight recorder which keeps the most recent entries that
+// weren't printed to the console, at all levels regardless of the logging level.
+// The entries are dumped when an error is logged, or when Dump is called,
+// so that the debug output leading up to an error is available without
+// rerunning the CLI with debug logging.
+type Recorder struct {
+	cfg RecorderConfig
+
+	mu      sync.Mutex
+	records []record
+	// next is the index of the oldest record, which is overwritten next
+	// when the buffer is full.
+	next int
+	// sink is where Dump writes entries. It's set by the most recent
+	// logger created with the recorder.
+	sink *recorderSink
+}
+
+// record is an entry kept by a Recorder.
+type record struct {
+	ent    zapcore.Entry
+	fields []zapcore.Field
+}
+
+// NewRecorder returns a flight recorder, which is added to a logger with WithRecorder.
+func NewRecorder(cfg RecorderConfig) *Recorder {
+	if cfg.Size == 0 {
+		cfg.Size = 100
+	}
+	return &Recorder{cfg: cfg}
+}
+
+// WithRecorder records the entries which aren't printed to the console with r,
+// and dumps them when an error is logged.
+//
+// Recording means that messages at all levels are formatted, so there is a
+// small performance cost to debug and trace messages.
+func WithRecorder(r *Recorder) Option {
+	return func(o *Options) {
+		o.Recorder = r
+	}
+}
+
+// Dump writes the recorded entries to the most recent logger created with the
+// recorder, and then clears them. It does nothing if there are no entries.
+func (r *Recorder) Dump() {
+	r.mu.Lock()
+	sink := r.sink
+	r.mu.Unlock()
+
+	if sink != nil {
+		r.dump(sink)
+	}
+}
+
+// add records an entry, overwriting the oldest entry if the buffer is full.
+func (r *Recorder) add(rec record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.records) < r.cfg.Size {
+		r.records = append(r.records, rec)
+		return
+	}
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+}
+
+// take returns the recorded entries from oldest to newest, and clears them.
+func (r *Recorder) take() []record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := append(r.records[r.next:len(r.records):len(r.records)], r.records[:r.next]...)
+	r.records = nil
+	r.next = 0
+	return out
+}
+
+// dump writes the recorded entries to sink and clears them.
+func (r *Recorder) dump(sink *recorderSink) {
+	records := r.take()
+	if len(records) == 0 {
+		return
+	}
+
+	if r.cfg.Console && sink.console != nil {
+		header := "recent debug output:\n"
+		if sink.noColor == nil || !*sink.noColor {
+			header = ansi.Reset + dim + "recent debug output:" + ansi.Reset + "\n"
+		}
+		// ignore errors, as there's nowhere to report them.
+		_, _ = sink.w.Write([]byte(header))
+		for _, rec := range records {
+			_ = sink.console.Write(rec.ent, rec.fields)
+		}
+	}
+
+	if r.cfg.File && sink.file != nil {
+		for _, rec := range records {
+			// the file log already contains the entries at the levels it enables.
+			if !sink.file.Enabled(rec.ent.Level) {
+				_ = sink.file.Write(rec.ent, rec.fields)
+			}
+		}
+	}
+}
+
+// recorderSink is where a Recorder dumps entries.
+type recorderSink struct {
+	// w is the console writer, which the header is written to.
+	w       zapcore.WriteSyncer
+	noColor *bool
+	// console writes entries to w, regardless of their level.
+	console zapcore.Core
+	// file is the file log core, if there is one.
+	file zapcore.Core
+}
+
+// recorderCore records the entries which aren't printed to the console,
+// and dumps them when an error is logged. It must be teed before the
+// other cores, so that the recorded entries are dumped before the error.
+type recorderCore struct {
+	rec  *Recorder
+	sink *recorderSink
+	// level and levels are the console logging levels.
+	// If levels is set, it's used instead of level.
+	level  zapcore.LevelEnabler
+	levels *NamedLevels
+	// context are the fields added with With().
+	context []zapcore.Field
+}
+
+func newRecorderCore(r *Recorder, ec *zapcore.EncoderConfig, o Options, level zapcore.LevelEnabler, file zapcore.Core) zapcore.Core {
+	ws := zapcore.AddSync(o.Writer)
+	allLevels := zap.LevelEnablerFunc(func(zapcore.Level) bool { return true })
+
+	sink := &recorderSink{
+		w:       ws,
+		noColor: o.NoColor,
+		console: zapcore.NewCore(newConsoleEncoder(ec, o, o.Writer), ws, allLevels),
+		file:    file,
+	}
+
+	r.mu.Lock()
+	r.sink = sink
+	r.mu.Unlock()
+
+	return &recorderCore{rec: r, sink: sink, level: level, levels: o.NamedLevels}
+}
+
+// Enabled implements zapcore.Core.
+// Entries at all levels are recorded.
+func (c *recorderCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// With implements zapcore.Core
+func (c *recorderCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.context = append(c.context[:len(c.context):len(c.context)], fields...)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *recorderCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
+
+// Write implements zapcore.Core
+func (c *recorderCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if !c.shown(ent) {
+		all := append(append(make([]zapcore.Field, 0, len(c.context)+len(fields)), c.context...), fields...)
+		c.rec.add(record{ent: ent, fields: all})
+		return nil
+	}
+	if ent.Level >= zapcore.ErrorLevel {
+		c.rec.dump(c.sink)
+	}
+	return nil
+}
+
+// Sync implements zapcore.Core
+func (c *recorderCore) Sync() error {
+	return nil
+}
+
+// shown returns true if the entry is printed to the console.
+func (c *recorderCore) shown(ent zapcore.Entry) bool {
+	if c.levels != nil {
+		return c.levels.EnabledFor(ent.LoggerName, ent.Level)
+	}
+	return c.level.Enabled(ent.Level)
+}
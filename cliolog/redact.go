@@ -0,0 +1,371 @@
+package cliolog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces secrets in redacted messages and fields.
+const Redacted = "[REDACTED]"
+
+// redactPattern is a pattern which detects secrets.
+// Matches are replaced with repl, which may refer to submatches.
+type redactPattern struct {
+	re   *regexp.Regexp
+	repl string
+}
+
+// defaultPatterns detect common secrets.
+var defaultPatterns = []redactPattern{
+	// AWS access key IDs.
+	{re: regexp.MustCompile(`\b(?:AKIA|ASIA|AROA|AIDA)[0-9A-Z]{16}\b`), repl: Redacted},
+	// AWS secret access keys and session tokens, when they are labelled.
+	{re: regexp.MustCompile(`(?i)(aws_?(?:secret_?access_?key|session_?token)["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]+`), repl: "${1}" + Redacted},
+	// bearer tokens, such as in Authorization headers.
+	{re: regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9\-._~+/]+=*`), repl: "${1}" + Redacted},
+	// JWTs.
+	{re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), repl: Redacted},
+	// PEM encoded private keys.
+	{re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`), repl: Redacted},
+}
+
+// defaultKeys are the key name rules for fields which are always redacted.
+var defaultKeys = []string{"*token*", "*password*"}
+
+// Redactor removes secrets from messages and fields.
+// Secrets are detected using registered values, patterns and key name rules.
+// It's safe for concurrent use.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	replacer *strings.Replacer
+	patterns []redactPattern
+	keys     []string
+}
+
+// NewRedactor returns a Redactor which detects AWS keys, bearer tokens, JWTs
+// and private keys, and redacts fields with keys matching *token* and *password*.
+func NewRedactor() *Redactor {
+	return &Redactor{
+		patterns: append([]redactPattern(nil), defaultPatterns...),
+		keys:     append([]string(nil), defaultKeys...),
+	}
+}
+
+// WithRedactor redacts secrets from messages and fields using r, before
+// they're written to the console, the file log, hooks or a recorder.
+func WithRedactor(r *Redactor) Option {
+	return func(o *Options) {
+		o.Redactor = r
+	}
+}
+
+// AddSecret adds a value which is always redacted, such as a token
+// which has been read from a credential store. Empty values are ignored.
+func (r *Redactor) AddSecret(value string) {
+	if value == "" {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.secrets {
+		if s == value {
+			return
+		}
+	}
+	r.secrets = append(r.secrets, value)
+
+	pairs := make([]string, 0, len(r.secrets)*2)
+	for _, s := range r.secrets {
+		pairs = append(pairs, s, Redacted)
+	}
+	r.replacer = strings.NewReplacer(pairs...)
+}
+
+// AddPattern adds a pattern which detects secrets. Matches are replaced with [REDACTED].
+func (r *Redactor) AddPattern(re *regexp.Regexp) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.patterns = append(r.patterns, redactPattern{re: re, repl: Redacted})
+}
+
+// AddKey adds a key name rule. Fields with keys matching the rule are always
+// redacted. Rules are matched case-insensitively with path.Match, so
+// '*secret*' matches 'client_secret'.
+func (r *Redactor) AddKey(pattern string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, strings.ToLower(pattern))
+}
+
+// Redact returns s with any secrets replaced with [REDACTED].
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.replacer != nil {
+		s = r.replacer.Replace(s)
+	}
+	for _, p := range r.patterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// sensitiveKey returns true if the key matches a key name rule.
+func (r *Redactor) sensitiveKey(key string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key = strings.ToLower(key)
+	for _, k := range r.keys {
+		if ok, _ := path.Match(k, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactField returns the field with any secrets replaced with [REDACTED].
+// Fields with sensitive keys are replaced entirely, and string, error and
+// fmt.Stringer values have secrets removed. The values in nested objects,
+// arrays and reflected values, such as those logged with zap.Any, are also redacted.
+func (r *Redactor) RedactField(f zapcore.Field) zapcore.Field {
+	switch f.Type {
+	case zapcore.SkipType, zapcore.NamespaceType:
+		return f
+	}
+
+	if r.sensitiveKey(f.Key) {
+		return zap.String(f.Key, Redacted)
+	}
+
+	switch f.Type {
+	case zapcore.StringType:
+		f.String = r.Redact(f.String)
+	case zapcore.ByteStringType:
+		return zap.String(f.Key, r.Redact(string(f.Interface.([]byte))))
+	case zapcore.ErrorType:
+		return zap.String(f.Key, r.Redact(f.Interface.(error).Error()))
+	case zapcore.StringerType:
+		return zap.String(f.Key, r.Redact(f.Interface.(fmt.Stringer).String()))
+	case zapcore.ObjectMarshalerType:
+		return zap.Object(f.Key, redactObject{r: r, m: f.Interface.(zapcore.ObjectMarshaler)})
+	case zapcore.ArrayMarshalerType:
+		return zap.Array(f.Key, redactArray{r: r, m: f.Interface.(zapcore.ArrayMarshaler)})
+	case zapcore.ReflectType:
+		if v, ok := r.redactReflected(f.Interface); ok {
+			return zap.Any(f.Key, v)
+		}
+	}
+	return f
+}
+
+// redactReflected redacts a value which is encoded with reflection.
+// The value is encoded to JSON to find secrets in it, and if there are any,
+// the decoded JSON with the secrets redacted is returned. Values which
+// can't be encoded to JSON, or which have secrets in fields which aren't
+// encoded to JSON, are returned as a redacted string.
+// It returns false if the value doesn't contain any secrets.
+func (r *Redactor) redactReflected(v interface{}) (interface{}, bool) {
+	if b, err := json.Marshal(v); err == nil {
+		var decoded interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if dec.Decode(&decoded) == nil {
+			if redacted, ok := r.redactValue(decoded); ok {
+				return redacted, true
+			}
+		}
+	}
+
+	// the console shows reflected values formatted with %+v,
+	// which includes unexported fields.
+	s := fmt.Sprintf("%+v", v)
+	if redacted := r.Redact(s); redacted != s {
+		return redacted, true
+	}
+	return v, false
+}
+
+// redactValue redacts a value decoded from JSON in place.
+// It returns false if the value doesn't contain any secrets.
+func (r *Redactor) redactValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		redacted := r.Redact(v)
+		return redacted, redacted != v
+	case map[string]interface{}:
+		changed := false
+		for k, val := range v {
+			if r.sensitiveKey(k) {
+				v[k] = Redacted
+				changed = true
+				continue
+			}
+			if redacted, ok := r.redactValue(val); ok {
+				v[k] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	case []interface{}:
+		changed := false
+		for i, val := range v {
+			if redacted, ok := r.redactValue(val); ok {
+				v[i] = redacted
+				changed = true
+			}
+		}
+		return v, changed
+	}
+	return v, false
+}
+
+// redactFields returns the fields with secrets redacted.
+func (r *Redactor) redactFields(fields []zapcore.Field) []zapcore.Field {
+	if len(fields) == 0 {
+		return fields
+	}
+	out := make([]zapcore.Field, len(fields))
+	for i, f := range fields {
+		out[i] = r.RedactField(f)
+	}
+	return out
+}
+
+// redactObject redacts the fields of a nested object.
+type redactObject struct {
+	r *Redactor
+	m zapcore.ObjectMarshaler
+}
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (o redactObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.m.MarshalLogObject(redactEncoder{ObjectEncoder: enc, r: o.r})
+}
+
+// redactEncoder redacts string values added to an object encoder.
+type redactEncoder struct {
+	zapcore.ObjectEncoder
+	r *Redactor
+}
+
+// AddString implements zapcore.ObjectEncoder
+func (e redactEncoder) AddString(key, value string) {
+	if e.r.sensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return
+	}
+	e.ObjectEncoder.AddString(key, e.r.Redact(value))
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (e redactEncoder) AddByteString(key string, value []byte) {
+	e.AddString(key, string(value))
+}
+
+// AddObject implements zapcore.ObjectEncoder
+func (e redactEncoder) AddObject(key string, m zapcore.ObjectMarshaler) error {
+	if e.r.sensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddObject(key, redactObject{r: e.r, m: m})
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (e redactEncoder) AddArray(key string, m zapcore.ArrayMarshaler) error {
+	if e.r.sensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	return e.ObjectEncoder.AddArray(key, redactArray{r: e.r, m: m})
+}
+
+// AddReflected implements zapcore.ObjectEncoder
+func (e redactEncoder) AddReflected(key string, v interface{}) error {
+	if e.r.sensitiveKey(key) {
+		e.ObjectEncoder.AddString(key, Redacted)
+		return nil
+	}
+	v, _ = e.r.redactReflected(v)
+	return e.ObjectEncoder.AddReflected(key, v)
+}
+
+// redactArray redacts the elements of a nested array.
+type redactArray struct {
+	r *Redactor
+	m zapcore.ArrayMarshaler
+}
+
+// MarshalLogArray implements zapcore.ArrayMarshaler
+func (a redactArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.m.MarshalLogArray(redactArrayEncoder{ArrayEncoder: enc, r: a.r})
+}
+
+// redactArrayEncoder redacts string values appended to an array encoder.
+type redactArrayEncoder struct {
+	zapcore.ArrayEncoder
+	r *Redactor
+}
+
+// AppendString implements zapcore.PrimitiveArrayEncoder
+func (e redactArrayEncoder) AppendString(value string) {
+	e.ArrayEncoder.AppendString(e.r.Redact(value))
+}
+
+// AppendByteString implements zapcore.PrimitiveArrayEncoder
+func (e redactArrayEncoder) AppendByteString(value []byte) {
+	e.AppendString(string(value))
+}
+
+// AppendObject implements zapcore.ArrayEncoder
+func (e redactArrayEncoder) AppendObject(m zapcore.ObjectMarshaler) error {
+	return e.ArrayEncoder.AppendObject(redactObject{r: e.r, m: m})
+}
+
+// AppendArray implements zapcore.ArrayEncoder
+func (e redactArrayEncoder) AppendArray(m zapcore.ArrayMarshaler) error {
+	return e.ArrayEncoder.AppendArray(redactArray{r: e.r, m: m})
+}
+
+// AppendReflected implements zapcore.ArrayEncoder
+func (e redactArrayEncoder) AppendReflected(v interface{}) error {
+	v, _ = e.r.redactReflected(v)
+	return e.ArrayEncoder.AppendReflected(v)
+}
+
+// redactCore redacts secrets from entries before they're written to the wrapped core.
+type redactCore struct {
+	zapcore.Core
+	r *Redactor
+}
+
+// With implements zapcore.Core
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.r.redactFields(fields)), r: c.r}
+}
+
+// Check implements zapcore.Core
+func (c *redactCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checkWrapped(c.Core, ent, ce, c.redact)
+}
+
+// redact redacts the message and fields of an entry which is being written.
+// The message is redacted when the entry is written rather than when it's
+// checked, so that disabled messages aren't redacted.
+func (c *redactCore) redact(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	ent.Message = c.r.Redact(ent.Message)
+	return ent, c.r.redactFields(fields)
+}
@@ -0,0 +1,167 @@
+//go:build go1.21
+
+package cliolog
+
+import (
+	"context"
+	"log/slog"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandlerOptions configure a slog.Handler created with NewSlogHandler.
+type SlogHandlerOptions struct {
+	// Core is the zap core which records are written to.
+	// If nil, a core is created with New using Level and Options.
+	Core zapcore.Core
+	// Level is the logging level used if Core is nil.
+	// If not set, the info level is used.
+	Level zap.AtomicLevel
+	// Options are passed to New if Core is nil.
+	Options []Option
+}
+
+// slogHandler is a slog.Handler which writes records to a zap core.
+type slogHandler struct {
+	core zapcore.Core
+}
+
+// NewSlogHandler returns a slog.Handler which writes records to a zap core.
+// By default the records are rendered with the console encoder, so that
+// messages logged with the log/slog package look the same as other clio
+// messages. Groups are rendered as namespaced keys, e.g. group.key:value.
+// It's only available when building with Go 1.21 or later.
+func NewSlogHandler(opts SlogHandlerOptions) slog.Handler {
+	core := opts.Core
+	if core == nil {
+		level := opts.Level
+		if level == (zap.AtomicLevel{}) {
+			level = zap.NewAtomicLevel()
+		}
+		core = New(level, opts.Options...).Core()
+	}
+	return &slogHandler{core: core}
+}
+
+// Enabled implements slog.Handler
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.Enabled(SlogLevel(level))
+}
+
+// Handle implements slog.Handler
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	ent := zapcore.Entry{
+		Level:   SlogLevel(r.Level),
+		Time:    r.Time,
+		Message: r.Message,
+	}
+	ce := h.core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+
+	fields := make([]zapcore.Field, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		fields = appendAttr(fields, a)
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs implements slog.Handler
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var fields []zapcore.Field
+	for _, a := range attrs {
+		fields = appendAttr(fields, a)
+	}
+	return &slogHandler{core: h.core.With(fields)}
+}
+
+// WithGroup implements slog.Handler
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{core: h.core.With([]zapcore.Field{zap.Namespace(name)})}
+}
+
+// SlogLevel converts a slog level to a zap level.
+// Levels below slog.LevelDebug are converted to TraceLevel.
+func SlogLevel(l slog.Level) zapcore.Level {
+	switch {
+	case l >= slog.LevelError:
+		return zapcore.ErrorLevel
+	case l >= slog.LevelWarn:
+		return zapcore.WarnLevel
+	case l >= slog.LevelInfo:
+		return zapcore.InfoLevel
+	case l >= slog.LevelDebug:
+		return zapcore.DebugLevel
+	default:
+		return TraceLevel
+	}
+}
+
+// appendAttr converts a slog attribute to zap fields and appends them to fields.
+func appendAttr(fields []zapcore.Field, a slog.Attr) []zapcore.Field {
+	a.Value = a.Value.Resolve()
+
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return fields
+		}
+		// groups with an empty key are inlined.
+		if a.Key == "" {
+			for _, ga := range attrs {
+				fields = appendAttr(fields, ga)
+			}
+			return fields
+		}
+		return append(fields, zap.Object(a.Key, slogGroup(attrs)))
+	}
+
+	// ignore empty attributes.
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return append(fields, zap.String(a.Key, a.Value.String()))
+	case slog.KindInt64:
+		return append(fields, zap.Int64(a.Key, a.Value.Int64()))
+	case slog.KindUint64:
+		return append(fields, zap.Uint64(a.Key, a.Value.Uint64()))
+	case slog.KindFloat64:
+		return append(fields, zap.Float64(a.Key, a.Value.Float64()))
+	case slog.KindBool:
+		return append(fields, zap.Bool(a.Key, a.Value.Bool()))
+	case slog.KindDuration:
+		return append(fields, zap.Duration(a.Key, a.Value.Duration()))
+	case slog.KindTime:
+		return append(fields, zap.Time(a.Key, a.Value.Time()))
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			return append(fields, zap.NamedError(a.Key, err))
+		}
+		return append(fields, zap.Any(a.Key, a.Value.Any()))
+	}
+}
+
+// slogGroup marshals a slog group as a zap object.
+type slogGroup []slog.Attr
+
+// MarshalLogObject implements zapcore.ObjectMarshaler
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	var fields []zapcore.Field
+	for _, a := range g {
+		fields = appendAttr(fields, a)
+	}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return nil
+}
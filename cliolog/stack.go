@@ -0,0 +1,175 @@
+package cliolog
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithErrorStacks captures a stack trace for error messages when debug
+// messages are enabled, such as when the logging level is debug or trace.
+//
+// On the console the stack trace is trimmed and printed dimmed beneath the
+// message: clio and zap frames are removed, and paths are shown relative to
+// their module. The full stack trace is written to the file log.
+func WithErrorStacks() Option {
+	return func(o *Options) {
+		o.ErrorStacks = true
+	}
+}
+
+// errorStacks returns the zap option which captures stack traces for error
+// messages when debug messages are enabled at level.
+//
+// zap decides whether to capture a stack trace by the level alone, so with
+// named levels a stack trace is captured if debug messages are enabled for
+// any logger name. newStackCore removes it from the entries of other loggers.
+func errorStacks(level zapcore.LevelEnabler) zap.Option {
+	return zap.AddStacktrace(zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+		return l >= zapcore.ErrorLevel && level.Enabled(zapcore.DebugLevel)
+	}))
+}
+
+// stackCore removes stack traces from entries whose logger doesn't have
+// debug messages enabled.
+type stackCore struct {
+	zapcore.Core
+	levels *NamedLevels
+}
+
+// newStackCore returns a core which only keeps the stack traces of entries
+// from loggers with debug messages enabled, when named levels are used.
+func newStackCore(core zapcore.Core, o Options) zapcore.Core {
+	if o.NamedLevels == nil {
+		return core
+	}
+	return &stackCore{Core: core, levels: o.NamedLevels}
+}
+
+// With implements zapcore.Core
+func (c *stackCore) With(fields []zapcore.Field) zapcore.Core {
+	return &stackCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check implements zapcore.Core
+func (c *stackCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return checkWrapped(c.Core, ent, ce, c.removeStack)
+}
+
+// removeStack removes the stack trace of an entry which is being written,
+// if its logger doesn't have debug messages enabled.
+func (c *stackCore) removeStack(ent zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	if !c.levels.EnabledFor(ent.LoggerName, zapcore.DebugLevel) {
+		ent.Stack = ""
+	}
+	return ent, fields
+}
+
+// stackFrame is a frame of a stack trace formatted by zap.
+type stackFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+// parseStack parses a stack trace formatted by zap, which has a line
+// with the function name followed by an indented file:line for each frame.
+// Lines which can't be parsed are ignored.
+func parseStack(stack string) []stackFrame {
+	var frames []stackFrame
+	lines := strings.Split(stack, "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		loc := strings.TrimPrefix(lines[i+1], "\t")
+		sep := strings.LastIndexByte(loc, ':')
+		if sep < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(loc[sep+1:])
+		if err != nil {
+			continue
+		}
+		frames = append(frames, stackFrame{function: lines[i], file: loc[:sep], line: line})
+	}
+	return frames
+}
+
+// trimStack returns the frames of a stack trace formatted by zap, with
+// the frames from clio, zap, the log packages and the runtime removed.
+// Frames from the standard library beneath the program's own frames, such
+// as those of the testing package, are also removed.
+func trimStack(stack string) []stackFrame {
+	var out []stackFrame
+	for _, f := range parseStack(stack) {
+		if isLoggingFrame(runtime.Frame{Function: f.function, File: f.file}) || strings.HasPrefix(f.function, "runtime.") {
+			continue
+		}
+		out = append(out, f)
+	}
+	for len(out) > 0 && out[len(out)-1].isEntrypoint() {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// isEntrypoint returns true if the frame is from the standard library or
+// the generated main function of a test binary.
+func (f stackFrame) isEntrypoint() bool {
+	if strings.HasSuffix(f.file, "_testmain.go") {
+		return true
+	}
+	pkg := funcPackage(f.function)
+	first, _, _ := strings.Cut(pkg, "/")
+	return pkg != "main" && !strings.Contains(first, ".")
+}
+
+// mainModule is the path of the main module, such as github.com/common-fate/granted.
+var mainModule = func() string {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		return bi.Main.Path
+	}
+	return ""
+}()
+
+// stackModule returns the module that the paths of frames are shown relative
+// to. Test binaries built with Go 1.19 and earlier don't record the main
+// module, so the package at the bottom of the stack is used instead.
+func stackModule(frames []stackFrame) string {
+	if mainModule != "" || len(frames) == 0 {
+		return mainModule
+	}
+	return strings.TrimSuffix(funcPackage(frames[len(frames)-1].function), "_test")
+}
+
+// funcPackage returns the import path of the package of a function,
+// such as github.com/common-fate/clio for github.com/common-fate/clio.(*Logger).Info.
+func funcPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/') + 1
+	if dot := strings.IndexByte(function[slash:], '.'); dot >= 0 {
+		return function[:slash+dot]
+	}
+	return function
+}
+
+// location returns the path and line of the frame relative to module, such as
+// cmd/main.go:12 for a frame in the main module. Frames from other modules
+// are shown with the import path of their package, such as
+// github.com/aws/smithy-go/middleware/step.go:34.
+func (f stackFrame) location(module string) string {
+	// external test packages are in the same directory as the package they test.
+	dir := strings.TrimSuffix(funcPackage(f.function), "_test")
+	if module != "" && (dir == module || strings.HasPrefix(dir, module+"/")) {
+		dir = strings.TrimPrefix(strings.TrimPrefix(dir, module), "/")
+	}
+	return path.Join(dir, path.Base(f.file)) + ":" + strconv.Itoa(f.line)
+}
+
+// shortFunction returns the function name without the package directory,
+// such as clio.(*Logger).Info.
+func (f stackFrame) shortFunction() string {
+	return f.function[strings.LastIndexByte(f.function, '/')+1:]
+}
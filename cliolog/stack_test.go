@@ -0,0 +1,41 @@
+package cliolog
+
+import "testing"
+
+func TestStackLocation(t *testing.T) {
+	frames := []stackFrame{
+		{function: "github.com/aws/smithy-go/middleware.decorateHandler.HandleMiddleware", file: "/go/pkg/mod/github.com/aws/smithy-go@v1.13.5/middleware/step.go", line: 34},
+		{function: "example.com/app/cmd.run", file: "/src/app/cmd/run.go", line: 12},
+		{function: "example.com/app.main", file: "/src/app/main.go", line: 5},
+	}
+
+	tests := []struct {
+		name       string
+		mainModule string
+		want       []string
+	}{
+		{
+			name:       "main module",
+			mainModule: "example.com/app",
+			want:       []string{"github.com/aws/smithy-go/middleware/step.go:34", "cmd/run.go:12", "main.go:5"},
+		},
+		{
+			// test binaries built with Go 1.19 don't record the main module.
+			name: "unknown main module",
+			want: []string{"github.com/aws/smithy-go/middleware/step.go:34", "cmd/run.go:12", "main.go:5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func(m string) { mainModule = m }(mainModule)
+			mainModule = tt.mainModule
+
+			module := stackModule(frames)
+			for i, f := range frames {
+				if got := f.location(module); got != tt.want[i] {
+					t.Errorf("location() = %q, want %q", got, tt.want[i])
+				}
+			}
+		})
+	}
+}
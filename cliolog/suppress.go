@@ -0,0 +1,234 @@
+package cliolog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// dedupCore collapses identical consecutive messages. The first message
+// is written, and further identical messages are counted rather than
+// written. When a different message is logged, or the core is synced,
+// a summary such as "retrying... (repeated 12 times)" is written.
+type dedupCore struct {
+	zapcore.Core
+	state *dedupState
+	// context are the fields added with With(). Messages are only
+	// duplicates if they were logged with the same context.
+	context []zapcore.Field
+}
+
+// dedupState is shared between a dedupCore and the cores derived from it with With().
+type dedupState struct {
+	mu      sync.Mutex
+	last    zapcore.Entry
+	fields  []zapcore.Field
+	context []zapcore.Field
+	// core is the core which wrote the last message.
+	core  zapcore.Core
+	count int
+}
+
+func newDedupCore(core zapcore.Core) zapcore.Core {
+	return &dedupCore{Core: core, state: &dedupState{}}
+}
+
+// With implements zapcore.Core
+func (c *dedupCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(context, c.context...)
+	context = append(context, fields...)
+	return &dedupCore{Core: c.Core.With(fields), state: c.state, context: context}
+}
+
+// Check implements zapcore.Core
+func (c *dedupCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Core.Enabled(ent.Level) {
+		return ce
+	}
+	return ce.AddCore(ent, c)
+}
+
+// Write implements zapcore.Core
+func (c *dedupCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	s := c.state
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.core != nil && isDuplicate(s.last, s.fields, ent, fields) && fieldsEqual(s.context, c.context) {
+		s.count++
+		return nil
+	}
+
+	err := s.flush()
+
+	s.last = ent
+	s.fields = append(s.fields[:0], fields...)
+	s.context = c.context
+	s.core = c.Core
+	s.count = 0
+
+	if werr := c.Core.Write(ent, fields); werr != nil {
+		return werr
+	}
+	return err
+}
+
+// Sync implements zapcore.Core
+func (c *dedupCore) Sync() error {
+	c.state.mu.Lock()
+	err := c.state.flush()
+	c.state.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return c.Core.Sync()
+}
+
+// flush writes a summary of the suppressed messages, if there are any.
+// The caller must hold s.mu.
+func (s *dedupState) flush() error {
+	if s.count == 0 {
+		return nil
+	}
+	times := "times"
+	if s.count == 1 {
+		times = "time"
+	}
+	ent := s.last
+	ent.Message = fmt.Sprintf("%s (repeated %d %s)", ent.Message, s.count, times)
+	s.count = 0
+	return s.core.Write(ent, s.fields)
+}
+
+// isDuplicate returns true if two entries have the same level, logger name, message and fields.
+func isDuplicate(a zapcore.Entry, aFields []zapcore.Field, b zapcore.Entry, bFields []zapcore.Field) bool {
+	if a.Level != b.Level || a.LoggerName != b.LoggerName || a.Message != b.Message {
+		return false
+	}
+	return fieldsEqual(aFields, bFields)
+}
+
+// fieldsEqual returns true if two lists of fields are equal.
+func fieldsEqual(a, b []zapcore.Field) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// rateLimitKey is the key of fields created with RateLimit.
+const rateLimitKey = "clio.ratelimit"
+
+// RateLimit returns a field which limits how often messages with the provided
+// key are printed to the console. At most one message is printed for each
+// interval, and the others are dropped. Messages are still written to the
+// file log, if one is configured.
+//
+// The field must be added to a logger with With(), rather than to an individual message.
+// The field is never encoded, so it doesn't appear in the output.
+//
+// Example:
+//
+//	logger.With(cliolog.RateLimit("retry", 5*time.Second)).Warn("retrying...")
+func RateLimit(key string, every time.Duration) zapcore.Field {
+	return zapcore.Field{Key: rateLimitKey, Type: zapcore.SkipType, String: key, Integer: int64(every)}
+}
+
+// rateLimits holds the time that a message was last printed for each rate limit key.
+var rateLimits = struct {
+	sync.Mutex
+	last map[string]time.Time
+}{last: map[string]time.Time{}}
+
+// rateLimitCore drops messages with a RateLimit field if a message with
+// the same key has been printed within the rate limit interval.
+type rateLimitCore struct {
+	zapcore.Core
+	key   string
+	every time.Duration
+}
+
+// With implements zapcore.Core
+func (c *rateLimitCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	for _, f := range fields {
+		if f.Key == rateLimitKey && f.Type == zapcore.SkipType {
+			clone.key = f.String
+			clone.every = time.Duration(f.Integer)
+		}
+	}
+	clone.Core = c.Core.With(fields)
+	return &clone
+}
+
+// Check implements zapcore.Core
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.key == "" {
+		return c.Core.Check(ent, ce)
+	}
+	// check that the level is enabled before using up the rate limit.
+	if !c.Core.Enabled(ent.Level) || !allowRateLimited(c.key, c.every, ent.Time) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// allowRateLimited returns true if a message with the key hasn't been printed within the interval.
+func allowRateLimited(key string, every time.Duration, now time.Time) bool {
+	rateLimits.Lock()
+	defer rateLimits.Unlock()
+
+	if last, ok := rateLimits.last[key]; ok && now.Sub(last) < every {
+		return false
+	}
+	rateLimits.last[key] = now
+	return true
+}
+
+// SamplingConfig configures sampling of debug and trace messages.
+// In each Tick interval, the First messages with a given level and message
+// are printed, and then every Thereafter-th message is printed.
+// See zapcore.NewSamplerWithOptions for details.
+type SamplingConfig struct {
+	Tick       time.Duration
+	First      int
+	Thereafter int
+}
+
+// debugSamplerCore samples debug and trace messages, to avoid floods of
+// debug output. Messages at higher levels are not sampled.
+type debugSamplerCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func newDebugSamplerCore(core zapcore.Core, cfg SamplingConfig) zapcore.Core {
+	return &debugSamplerCore{
+		Core:    core,
+		sampled: zapcore.NewSamplerWithOptions(core, cfg.Tick, cfg.First, cfg.Thereafter),
+	}
+}
+
+// With implements zapcore.Core
+func (c *debugSamplerCore) With(fields []zapcore.Field) zapcore.Core {
+	return &debugSamplerCore{
+		Core:    c.Core.With(fields),
+		sampled: c.sampled.With(fields),
+	}
+}
+
+// Check implements zapcore.Core
+func (c *debugSamplerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level <= zapcore.DebugLevel {
+		return c.sampled.Check(ent, ce)
+	}
+	return c.Core.Check(ent, ce)
+}
@@ -0,0 +1,38 @@
+package cliolog
+
+import (
+	"io"
+	"os"
+
+	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
+)
+
+// NewColorable returns a writer to f which translates ANSI colors for
+// Windows consoles, like colorable.NewColorable. Unlike the writer returned
+// by colorable, it has an Fd method, so that the console encoder can detect
+// whether f is a terminal and read its width.
+func NewColorable(f *os.File) io.Writer {
+	return &colorableFile{Writer: colorable.NewColorable(f), file: f}
+}
+
+// colorableFile is a colorable writer to a file.
+type colorableFile struct {
+	io.Writer
+	file *os.File
+}
+
+// Fd returns the file descriptor of the underlying file.
+func (w *colorableFile) Fd() uintptr {
+	return w.file.Fd()
+}
+
+// isTerminal returns true if w is a terminal.
+// It's a variable so that it can be replaced in tests.
+var isTerminal = func(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
@@ -0,0 +1,123 @@
+package cliolog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TimeFormat controls how the time of each message is shown on the console.
+type TimeFormat int
+
+const (
+	// TimeNone doesn't show the time. It's the default.
+	TimeNone TimeFormat = iota
+	// TimeWall shows the wall clock time, such as 15:04:05.000.
+	TimeWall
+	// TimeElapsed shows the time elapsed since the program started, such as +1.234s.
+	TimeElapsed
+	// TimeDelta shows the time elapsed since the previous message, such as +0.012s.
+	TimeDelta
+)
+
+// String returns the name of the time format, as parsed by ParseTimeFormat.
+func (f TimeFormat) String() string {
+	switch f {
+	case TimeWall:
+		return "wall"
+	case TimeElapsed:
+		return "elapsed"
+	case TimeDelta:
+		return "delta"
+	default:
+		return "none"
+	}
+}
+
+// ParseTimeFormat parses a time format name: none, wall, elapsed or delta.
+// The names are case-insensitive.
+func ParseTimeFormat(text string) (TimeFormat, error) {
+	switch strings.ToLower(text) {
+	case "none", "":
+		return TimeNone, nil
+	case "wall":
+		return TimeWall, nil
+	case "elapsed":
+		return TimeElapsed, nil
+	case "delta":
+		return TimeDelta, nil
+	}
+	return TimeNone, fmt.Errorf("unrecognized time format: %q", text)
+}
+
+// WithTimeFormat shows the time of each message on the console, dimmed before
+// the level indicator. Output messages, such as those printed by clio.Log,
+// never show the time so that they can be piped.
+func WithTimeFormat(f TimeFormat) Option {
+	return func(o *Options) {
+		o.TimeFormat = f
+	}
+}
+
+// start is the time the program started, used by TimeElapsed.
+var start = time.Now()
+
+// lastTime holds the time of the previous message, used by TimeDelta.
+// It's shared between loggers, as they write to the same console.
+var lastTime struct {
+	sync.Mutex
+	t     time.Time
+	delta time.Duration
+}
+
+// timeEncoder returns the console time encoder for the format.
+func timeEncoder(f TimeFormat) zapcore.TimeEncoder {
+	switch f {
+	case TimeWall:
+		return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+			pae.AppendString(t.Format("15:04:05.000"))
+		}
+	case TimeElapsed:
+		return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+			pae.AppendString(formatSeconds(t.Sub(start)))
+		}
+	case TimeDelta:
+		return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {
+			pae.AppendString(formatSeconds(delta(t)))
+		}
+	default:
+		// no-op time encoder.
+		return func(t time.Time, pae zapcore.PrimitiveArrayEncoder) {}
+	}
+}
+
+// delta returns the time since the previous message. An entry which is
+// encoded more than once, such as for an extra writer, has the same delta each time.
+// Entries older than the previous message, such as those dumped by a Recorder,
+// have a delta of zero and don't change the delta of the next message.
+func delta(t time.Time) time.Duration {
+	lastTime.Lock()
+	defer lastTime.Unlock()
+
+	if t.Equal(lastTime.t) {
+		return lastTime.delta
+	}
+	if t.Before(lastTime.t) {
+		return 0
+	}
+	prev := lastTime.t
+	if prev.IsZero() {
+		prev = start
+	}
+	lastTime.t = t
+	lastTime.delta = t.Sub(prev)
+	return lastTime.delta
+}
+
+// formatSeconds formats a duration as seconds with millisecond precision, such as +1.234s.
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("+%.3fs", d.Seconds())
+}
@@ -0,0 +1,30 @@
+package cliolog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeltaOutOfOrder(t *testing.T) {
+	lastTime.Lock()
+	lastTime.t, lastTime.delta = time.Time{}, 0
+	lastTime.Unlock()
+
+	now := time.Now()
+	steps := []struct {
+		name string
+		t    time.Time
+		want time.Duration
+	}{
+		{name: "first", t: now, want: now.Sub(start)},
+		{name: "next", t: now.Add(50 * time.Millisecond), want: 50 * time.Millisecond},
+		// an older entry, such as one dumped by a recorder.
+		{name: "older", t: now.Add(-50 * time.Millisecond), want: 0},
+		{name: "after older", t: now.Add(100 * time.Millisecond), want: 50 * time.Millisecond},
+	}
+	for _, s := range steps {
+		if got := delta(s.t); got != s.want {
+			t.Errorf("%s: delta() = %v, want %v", s.name, got, s.want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package cliolog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithPretty renders nested objects and arrays, such as those logged with
+// zap.Object, as an indented YAML-like tree beneath the message:
+//
+//	[i] calling API  	url:http://example.com
+//	    user:
+//	      id: 1
+//	      roles:
+//	        - admin
+//
+// Pretty rendering is only used for writers which are terminals. Other
+// writers, such as pipes and files, keep nested values on a single line.
+func WithPretty() Option {
+	return func(o *Options) {
+		o.Pretty = true
+	}
+}
+
+// isNested returns true if a field is a nested object or array, which is
+// rendered as a tree in pretty mode.
+func isNested(f zapcore.Field) bool {
+	return f.Type == zapcore.ObjectMarshalerType || f.Type == zapcore.ArrayMarshalerType
+}
+
+// treeIndent is the indent of the top level of a tree beneath the message.
+const treeIndent = 4
+
+// treeEncoder renders objects and arrays as an indented YAML-like tree,
+// with one line per value.
+type treeEncoder struct {
+	buf    *buffer.Buffer
+	indent int
+	// item is true if the next line is the first line of an array item,
+	// which is prefixed with a dash.
+	item bool
+}
+
+// appendTree renders nested fields as a tree, with each line preceded by a newline.
+func appendTree(buf *buffer.Buffer, fields []zapcore.Field) {
+	enc := &treeEncoder{buf: buf, indent: treeIndent}
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+}
+
+// line starts a new line at the current indent.
+func (enc *treeEncoder) line() {
+	enc.buf.AppendByte('\n')
+	if enc.item {
+		enc.buf.AppendString(strings.Repeat(" ", enc.indent-2) + "- ")
+		enc.item = false
+		return
+	}
+	enc.buf.AppendString(strings.Repeat(" ", enc.indent))
+}
+
+// addKey starts a new line with a key.
+func (enc *treeEncoder) addKey(key string) {
+	enc.line()
+	enc.buf.AppendString(key)
+	enc.buf.AppendByte(':')
+}
+
+// addValue adds a value after a key, or as an array item.
+func (enc *treeEncoder) addValue(s string) {
+	if enc.item {
+		enc.line()
+	} else {
+		enc.buf.AppendByte(' ')
+	}
+	// keep each value on one line.
+	enc.buf.AppendString(strings.NewReplacer("\n", `\n`, "\r", `\r`).Replace(s))
+}
+
+// nested renders an object or array beneath the current line.
+// The indent is restored afterwards, as namespaces opened within the
+// object increase it.
+func (enc *treeEncoder) nested(marshal func() error, empty string) error {
+	indent := enc.indent
+	enc.indent += 2
+	enc.item = false
+	start := enc.buf.Len()
+	err := marshal()
+	enc.indent = indent
+	if enc.buf.Len() == start {
+		enc.buf.AppendString(" " + empty)
+	}
+	return err
+}
+
+// AddArray implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddArray(key string, arr zapcore.ArrayMarshaler) error {
+	enc.addKey(key)
+	return enc.nested(func() error { return arr.MarshalLogArray(enc) }, "[]")
+}
+
+// AddObject implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddObject(key string, obj zapcore.ObjectMarshaler) error {
+	enc.addKey(key)
+	return enc.nested(func() error { return obj.MarshalLogObject(enc) }, "{}")
+}
+
+// AppendArray implements zapcore.ArrayEncoder
+func (enc *treeEncoder) AppendArray(arr zapcore.ArrayMarshaler) error {
+	enc.line()
+	enc.buf.AppendByte('-')
+	return enc.nested(func() error { return arr.MarshalLogArray(enc) }, "[]")
+}
+
+// AppendObject implements zapcore.ArrayEncoder.
+// The first field of the object is on the same line as the item's dash.
+func (enc *treeEncoder) AppendObject(obj zapcore.ObjectMarshaler) error {
+	indent := enc.indent
+	enc.indent += 2
+	enc.item = true
+	err := obj.MarshalLogObject(enc)
+	enc.indent = indent
+	if enc.item {
+		// the object was empty.
+		enc.item = false
+		enc.line()
+		enc.buf.AppendString("- {}")
+	}
+	return err
+}
+
+// OpenNamespace implements zapcore.ObjectEncoder
+func (enc *treeEncoder) OpenNamespace(key string) {
+	enc.addKey(key)
+	enc.indent += 2
+}
+
+// AddReflected implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddReflected(key string, obj interface{}) error {
+	enc.addKey(key)
+	enc.addValue(fmt.Sprintf("%+v", obj))
+	return nil
+}
+
+// AppendReflected implements zapcore.ArrayEncoder
+func (enc *treeEncoder) AppendReflected(val interface{}) error {
+	enc.AppendString(fmt.Sprintf("%+v", val))
+	return nil
+}
+
+// AddBinary implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddBinary(key string, val []byte) {
+	enc.AddString(key, fmt.Sprintf("%x", val))
+}
+
+// AddByteString implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddByteString(key string, val []byte) { enc.AddString(key, string(val)) }
+
+// AddBool implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddBool(key string, val bool) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddComplex128 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddComplex128(key string, val complex128) {
+	enc.AddString(key, fmt.Sprint(val))
+}
+
+// AddComplex64 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddComplex64(key string, val complex64) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddDuration implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddDuration(key string, val time.Duration) { enc.AddString(key, val.String()) }
+
+// AddFloat64 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddFloat64(key string, val float64) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddFloat32 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddFloat32(key string, val float32) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddInt implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddInt(key string, val int) { enc.AddInt64(key, int64(val)) }
+
+// AddInt64 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddInt64(key string, val int64) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddInt32 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddInt32(key string, val int32) { enc.AddInt64(key, int64(val)) }
+
+// AddInt16 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddInt16(key string, val int16) { enc.AddInt64(key, int64(val)) }
+
+// AddInt8 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddInt8(key string, val int8) { enc.AddInt64(key, int64(val)) }
+
+// AddString implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddString(key, val string) {
+	enc.addKey(key)
+	enc.addValue(val)
+}
+
+// AddTime implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddTime(key string, val time.Time) {
+	enc.AddString(key, val.Format(time.RFC3339))
+}
+
+// AddUint implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUint(key string, val uint) { enc.AddUint64(key, uint64(val)) }
+
+// AddUint64 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUint64(key string, val uint64) { enc.AddString(key, fmt.Sprint(val)) }
+
+// AddUint32 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUint32(key string, val uint32) { enc.AddUint64(key, uint64(val)) }
+
+// AddUint16 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUint16(key string, val uint16) { enc.AddUint64(key, uint64(val)) }
+
+// AddUint8 implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUint8(key string, val uint8) { enc.AddUint64(key, uint64(val)) }
+
+// AddUintptr implements zapcore.ObjectEncoder
+func (enc *treeEncoder) AddUintptr(key string, val uintptr) { enc.AddUint64(key, uint64(val)) }
+
+// AppendBool implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendBool(val bool) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendByteString implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendByteString(val []byte) { enc.AppendString(string(val)) }
+
+// AppendComplex128 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendComplex128(val complex128) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendComplex64 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendComplex64(val complex64) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendDuration implements zapcore.ArrayEncoder
+func (enc *treeEncoder) AppendDuration(val time.Duration) { enc.AppendString(val.String()) }
+
+// AppendFloat64 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendFloat64(val float64) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendFloat32 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendFloat32(val float32) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendInt implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendInt(val int) { enc.AppendInt64(int64(val)) }
+
+// AppendInt64 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendInt64(val int64) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendInt32 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendInt32(val int32) { enc.AppendInt64(int64(val)) }
+
+// AppendInt16 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendInt16(val int16) { enc.AppendInt64(int64(val)) }
+
+// AppendInt8 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendInt8(val int8) { enc.AppendInt64(int64(val)) }
+
+// AppendString implements zapcore.PrimitiveArrayEncoder.
+// Array items are prefixed with a dash.
+func (enc *treeEncoder) AppendString(val string) {
+	enc.indent += 2
+	enc.item = true
+	enc.addValue(val)
+	enc.indent -= 2
+}
+
+// AppendTime implements zapcore.ArrayEncoder
+func (enc *treeEncoder) AppendTime(val time.Time) { enc.AppendString(val.Format(time.RFC3339)) }
+
+// AppendUint implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUint(val uint) { enc.AppendUint64(uint64(val)) }
+
+// AppendUint64 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUint64(val uint64) { enc.AppendString(fmt.Sprint(val)) }
+
+// AppendUint32 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUint32(val uint32) { enc.AppendUint64(uint64(val)) }
+
+// AppendUint16 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUint16(val uint16) { enc.AppendUint64(uint64(val)) }
+
+// AppendUint8 implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUint8(val uint8) { enc.AppendUint64(uint64(val)) }
+
+// AppendUintptr implements zapcore.PrimitiveArrayEncoder
+func (enc *treeEncoder) AppendUintptr(val uintptr) { enc.AppendUint64(uint64(val)) }
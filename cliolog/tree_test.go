@@ -0,0 +1,132 @@
+package cliolog
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// testUser is an object with nested objects and arrays, for testing pretty output.
+type testUser struct {
+	ID    int
+	Roles []string
+	Teams []testObject
+}
+
+func (u testUser) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddInt("id", u.ID)
+	if err := enc.AddArray("roles", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, r := range u.Roles {
+			arr.AppendString(r)
+		}
+		return nil
+	})); err != nil {
+		return err
+	}
+	return enc.AddArray("teams", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, t := range u.Teams {
+			if err := arr.AppendObject(t); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+func TestPretty(t *testing.T) {
+	user := testUser{
+		ID:    1,
+		Roles: []string{"admin", "multi\nline"},
+		Teams: []testObject{{ID: 2, Name: "a"}, {ID: 3, Name: "b"}},
+	}
+
+	tests := []struct {
+		name        string
+		notTerminal bool
+		with        []zapcore.Field
+		fields      []zapcore.Field
+		want        string
+	}{
+		{
+			name:   "object",
+			fields: []zapcore.Field{zap.String("url", "http://example.com"), zap.Object("user", user)},
+			want: "[i] calling API  \turl:http://example.com\n" +
+				"    user:\n" +
+				"      id: 1\n" +
+				"      roles:\n" +
+				"        - admin\n" +
+				"        - multi\\nline\n" +
+				"      teams:\n" +
+				"        - id: 2\n" +
+				"          name: a\n" +
+				"        - id: 3\n" +
+				"          name: b\n",
+		},
+		{
+			name:   "only nested fields",
+			fields: []zapcore.Field{zap.Strings("ids", []string{"a", "b"}), zap.Strings("empty", nil)},
+			want:   "[i] calling API\n    ids:\n      - a\n      - b\n    empty: []\n",
+		},
+		{
+			name:   "context and namespaces",
+			with:   []zapcore.Field{zap.Object("team", testObject{ID: 2, Name: "a"})},
+			fields: []zapcore.Field{zap.Namespace("http"), zap.Int("status", 500), zap.Ints("ids", []int{1})},
+			want:   "[i] calling API  \thttp.status:500\n    http.ids:\n      - 1\n    team:\n      id: 2\n      name: a\n",
+		},
+		{
+			name: "namespace in object",
+			fields: []zapcore.Field{
+				zap.Object("req", zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+					enc.OpenNamespace("http")
+					enc.AddInt("status", 500)
+					return nil
+				})),
+				zap.Object("team", testObject{ID: 2, Name: "a"}),
+			},
+			want: "[i] calling API\n    req:\n      http:\n        status: 500\n    team:\n      id: 2\n      name: a\n",
+		},
+		{
+			name:        "not a terminal",
+			notTerminal: true,
+			fields:      []zapcore.Field{zap.String("url", "http://example.com"), zap.Object("team", testObject{ID: 2, Name: "a"})},
+			want:        "[i] calling API  \turl:http://example.com\tteam:{id:2,name:a}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer replaceIsTerminal(!tt.notTerminal)()
+
+			var b bytes.Buffer
+			noColor := true
+			log := New(zap.NewAtomicLevelAt(zap.InfoLevel), WithWriter(&b), WithNoColor(&noColor), WithPretty())
+			log.With(tt.with...).Info("calling API", tt.fields...)
+
+			if got := b.String(); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// replaceIsTerminal makes all writers appear to be terminals, or not,
+// returning a function to restore the check.
+func replaceIsTerminal(terminal bool) func() {
+	orig := isTerminal
+	isTerminal = func(io.Writer) bool { return terminal }
+	return func() { isTerminal = orig }
+}
+
+func TestNewColorableFd(t *testing.T) {
+	w := NewColorable(os.Stderr)
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		t.Fatal("NewColorable() doesn't have an Fd method")
+	}
+	if f.Fd() != os.Stderr.Fd() {
+		t.Errorf("Fd() = %d, want %d", f.Fd(), os.Stderr.Fd())
+	}
+}
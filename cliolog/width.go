@@ -0,0 +1,60 @@
+package cliolog
+
+import (
+	"io"
+	"sync"
+)
+
+// terminalWidth returns the width of w in columns,
+// or 0 if w isn't a terminal.
+func terminalWidth(w io.Writer) int {
+	if !isTerminal(w) {
+		return 0
+	}
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return 0
+	}
+	return widths.get(f.Fd())
+}
+
+// widths caches the widths of terminals.
+var widths widthCache
+
+// widthCache caches the widths of terminals by file descriptor. The cache is
+// cleared when a terminal is resized, on platforms which notify us of resizes.
+type widthCache struct {
+	once sync.Once
+	// cached is false if the platform can't notify us of resizes,
+	// in which case the width is read for every message.
+	cached bool
+
+	mu  sync.Mutex
+	fds map[uintptr]int
+}
+
+func (c *widthCache) get(fd uintptr) int {
+	c.once.Do(func() {
+		c.fds = map[uintptr]int{}
+		c.cached = watchResize(c.clear)
+	})
+	if !c.cached {
+		return fdWidth(fd)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	width, ok := c.fds[fd]
+	if !ok {
+		width = fdWidth(fd)
+		c.fds[fd] = width
+	}
+	return width
+}
+
+// clear is called when a terminal is resized.
+func (c *widthCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fds = map[uintptr]int{}
+}
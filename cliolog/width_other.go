@@ -0,0 +1,13 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris && !windows
+
+package cliolog
+
+// fdWidth returns 0, as the width of terminals can't be read on this platform.
+func fdWidth(fd uintptr) int {
+	return 0
+}
+
+// watchResize returns false, as terminal resizes can't be watched on this platform.
+func watchResize(onResize func()) bool {
+	return false
+}
@@ -0,0 +1,32 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package cliolog
+
+import (
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// fdWidth returns the width of the terminal fd in columns, or 0 if it can't be read.
+func fdWidth(fd uintptr) int {
+	ws, err := unix.IoctlGetWinsize(int(fd), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
+
+// watchResize calls onResize whenever the terminal is resized (SIGWINCH),
+// returning true as resizes can be watched.
+func watchResize(onResize func()) bool {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, unix.SIGWINCH)
+	go func() {
+		for range ch {
+			onResize()
+		}
+	}()
+	return true
+}
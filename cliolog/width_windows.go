@@ -0,0 +1,21 @@
+//go:build windows
+
+package cliolog
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// fdWidth returns the width of the console fd in columns, or 0 if it can't be read.
+func fdWidth(fd uintptr) int {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(fd), &info); err != nil {
+		return 0
+	}
+	return int(info.Window.Right - info.Window.Left + 1)
+}
+
+// watchResize returns false, as Windows doesn't signal console resizes.
+func watchResize(onResize func()) bool {
+	return false
+}
@@ -0,0 +1,104 @@
+package cliolog
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// WithNoWrap disables wrapping messages at the width of the terminal.
+//
+// By default, messages written to a terminal are wrapped on word boundaries
+// at the terminal's width, and continuation lines are indented to line up
+// with the message rather than the level symbol:
+//
+//	[i] a long message which doesn't fit on
+//	    one line  	account:123456789012
+//
+// Messages written to other writers, such as pipes and files, are never wrapped.
+func WithNoWrap() Option {
+	return func(o *Options) {
+		o.NoWrap = true
+	}
+}
+
+// tabWidth is the width of tab stops in terminals.
+const tabWidth = 8
+
+// advance returns the column after showing r at col.
+func advance(col int, r rune) int {
+	if r == '\t' {
+		return col + tabWidth - col%tabWidth
+	}
+	return col + 1
+}
+
+// isSpace returns true if r is a word boundary.
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t'
+}
+
+// wrap wraps each line of b on word boundaries so that it fits in width
+// columns, indenting continuation lines by indent columns. ANSI escape
+// sequences don't take up any columns. Words which are wider than a line
+// are never broken.
+func wrap(b []byte, width, indent int) []byte {
+	out := make([]byte, 0, len(b)+len(b)/width*(indent+1))
+	// col is the column at the end of out.
+	col := 0
+	// brk is the index in out of the last word boundary on the line, or -1.
+	brk := -1
+	// text is true once a word has been shown on the line after the indent,
+	// as the level symbol and indentation aren't broken.
+	text := false
+
+	for i := 0; i < len(b); {
+		// escape sequences are copied without moving the column.
+		if n := escapeLen(b[i:]); n > 0 {
+			out = append(out, b[i:i+n]...)
+			i += n
+			continue
+		}
+
+		r, size := utf8.DecodeRune(b[i:])
+		switch {
+		case r == '\n':
+			col, brk, text = 0, -1, false
+		case isSpace(r):
+			if text {
+				brk = len(out)
+			}
+		case advance(col, r) > width && brk >= 0:
+			// move the words after the last boundary to a new line.
+			rest := append([]byte(nil), out[brk+1:]...)
+			out = append(bytes.TrimRight(out[:brk], " \t"), '\n')
+			out = append(out, bytes.Repeat([]byte{' '}, indent)...)
+			out = append(out, rest...)
+			brk = -1
+			col = indent + lineWidth(rest)
+		}
+		if !isSpace(r) && r != '\n' && col >= indent {
+			text = true
+		}
+
+		out = append(out, b[i:i+size]...)
+		if r != '\n' {
+			col = advance(col, r)
+		}
+		i += size
+	}
+	return out
+}
+
+// escapeLen returns the length of the ANSI escape sequence at the start of b,
+// or 0 if b doesn't start with an escape sequence.
+func escapeLen(b []byte) int {
+	if len(b) < 2 || b[0] != '\x1b' || b[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(b); i++ {
+		if b[i] >= 0x40 && b[i] <= 0x7e {
+			return i + 1
+		}
+	}
+	return len(b)
+}
@@ -0,0 +1,153 @@
+package cliolog
+
+import (
+	"bytes"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		name   string
+		give   string
+		width  int
+		indent int
+		want   string
+	}{
+		{
+			name:   "fits",
+			give:   "[i] short message",
+			width:  20,
+			indent: 4,
+			want:   "[i] short message",
+		},
+		{
+			name:   "hanging indent",
+			give:   "[i] a long message which does not fit",
+			width:  20,
+			indent: 4,
+			want:   "[i] a long message\n    which does not\n    fit",
+		},
+		{
+			name:   "long words aren't broken",
+			give:   "[i] see https://example.com/a/very/long/url for details",
+			width:  20,
+			indent: 4,
+			want:   "[i] see\n    https://example.com/a/very/long/url\n    for details",
+		},
+		{
+			name:   "tabs",
+			give:   "[i] message  \tkey:value\tother:value",
+			width:  26,
+			indent: 4,
+			want:   "[i] message  \tkey:value\n    other:value",
+		},
+		{
+			name:   "escape sequences",
+			give:   "\x1b[0m[i] colored \x1b[0m\x1b[38;5;240mwords here",
+			width:  16,
+			indent: 4,
+			want:   "\x1b[0m[i] colored\n    \x1b[0m\x1b[38;5;240mwords here",
+		},
+		{
+			name:   "existing lines",
+			give:   "[i] first line\nsecond line is longer",
+			width:  16,
+			indent: 4,
+			want:   "[i] first line\nsecond line is\n    longer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(wrap([]byte(tt.give), tt.width, tt.indent)); got != tt.want {
+				t.Errorf("wrap() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleEncoderWrap(t *testing.T) {
+	ec := zap.NewDevelopmentEncoderConfig()
+	ec.EncodeLevel = SymbolLevelEncoder
+	ec.EncodeTime = timeEncoder(TimeNone)
+	noColor := true
+
+	tests := []struct {
+		name   string
+		logger string
+		want   string
+	}{
+		{
+			name: "status message",
+			want: "[i] a long message which does\n    not fit on one line\n    account:1\n",
+		},
+		{
+			name:   "output message",
+			logger: NoPrefixName,
+			want:   "a long message which does not fit on one line  \taccount:1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc := NewConsoleEncoder(&ec, &noColor).(*consoleEncoder)
+			enc.width = func() int { return 30 }
+
+			ent := zapcore.Entry{Level: zapcore.InfoLevel, LoggerName: tt.logger, Message: "a long message which does not fit on one line"}
+			buf, err := enc.EncodeEntry(ent, []zapcore.Field{zap.Int("account", 1)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleEncoderWrapTreeAndStack(t *testing.T) {
+	ec := zap.NewDevelopmentEncoderConfig()
+	ec.EncodeLevel = SymbolLevelEncoder
+	ec.EncodeTime = timeEncoder(TimeNone)
+	noColor := true
+
+	// show the stack relative to the module of this test, whichever Go version builds it.
+	defer func(m string) { mainModule = m }(mainModule)
+	mainModule = "github.com/common-fate/clio"
+
+	enc := NewConsoleEncoder(&ec, &noColor).(*consoleEncoder)
+	enc.pretty = true
+	enc.width = func() int { return 30 }
+
+	ent := zapcore.Entry{
+		Level:   zapcore.ErrorLevel,
+		Message: "a long message which does not fit",
+		Stack:   "example.com/app/pkg.someVeryLongFunctionName\n\t/src/app/pkg/file.go:12",
+	}
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{zap.Object("team", testObject{ID: 2, Name: "a name which is longer than the terminal"})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[✘] a long message which does\n    not fit\n" +
+		"    team:\n      id: 2\n      name: a name which is longer than the terminal\n" +
+		"    at pkg.someVeryLongFunctionName (example.com/app/pkg/file.go:12)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestNoWrapWhenNotTerminal(t *testing.T) {
+	ec := zap.NewDevelopmentEncoderConfig()
+	enc := newConsoleEncoder(&ec, Options{}, &bytes.Buffer{}).(*consoleEncoder)
+	if enc.width != nil {
+		t.Error("messages are wrapped for a writer which isn't a terminal")
+	}
+
+	defer replaceIsTerminal(true)()
+	enc = newConsoleEncoder(&ec, Options{NoWrap: true}, &bytes.Buffer{}).(*consoleEncoder)
+	if enc.width != nil {
+		t.Error("messages are wrapped with NoWrap set")
+	}
+}
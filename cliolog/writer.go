@@ -0,0 +1,114 @@
+package cliolog
+
+import (
+	"bytes"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WriterOption configures a Writer created with NewWriter.
+type WriterOption func(*Writer)
+
+// WriterFields adds static fields to every line logged by the Writer.
+func WriterFields(fields ...zap.Field) WriterOption {
+	return func(w *Writer) {
+		w.logger = w.logger.With(fields...)
+	}
+}
+
+// WriterName sets the name of the logger used by the Writer, which can
+// be used to filter lines with per-name log levels.
+func WriterName(name string) WriterOption {
+	return func(w *Writer) {
+		w.logger = w.logger.Named(name)
+	}
+}
+
+// WriterPrefix adds a prefix to the message of every line logged by the Writer,
+// such as the name of the subprocess which wrote the line. A ": " separator
+// is added after the prefix.
+func WriterPrefix(prefix string) WriterOption {
+	return func(w *Writer) {
+		w.prefix = prefix + ": "
+	}
+}
+
+// Writer is an io.WriteCloser which logs each line written to it as a
+// separate message. Lines may be split across several calls to Write, and
+// both LF and CRLF line endings are supported. Blank lines are not logged.
+//
+// Close must be called to log any partial line remaining in the buffer.
+type Writer struct {
+	logger *zap.Logger
+	level  zapcore.Level
+	prefix string
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// NewWriter returns a Writer which logs each line written to it to logger at the provided level.
+//
+// Example:
+//
+//	w := cliolog.NewWriter(logger, zapcore.DebugLevel, cliolog.WriterPrefix("terraform"))
+//	defer w.Close()
+//	cmd.Stderr = w
+func NewWriter(logger *zap.Logger, level zapcore.Level, opts ...WriterOption) *Writer {
+	w := &Writer{
+		logger: logger,
+		level:  level,
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// Write implements io.Writer.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf.Write(p)
+
+	for {
+		line, err := w.buf.ReadBytes('\n')
+		if err != nil {
+			// no newline was found, so keep the partial line for the next write.
+			w.buf.Reset()
+			w.buf.Write(line)
+			break
+		}
+		w.log(line)
+	}
+
+	return len(p), nil
+}
+
+// Close logs any partial line remaining in the buffer.
+// It implements io.Closer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.log(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+// log logs a single line, removing the line ending.
+func (w *Writer) log(line []byte) {
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(bytes.TrimSpace(line)) == 0 {
+		return
+	}
+	if ce := w.logger.Check(w.level, w.prefix+string(line)); ce != nil {
+		ce.Write()
+	}
+}
@@ -0,0 +1,60 @@
+package clio
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// contextKey is the key used to store a contextValue in a context.Context.
+type contextKey struct{}
+
+// contextValue holds the logger and fields attached to a context.
+type contextValue struct {
+	logger *zap.SugaredLogger
+	fields []any
+}
+
+// WithContext returns a copy of ctx which carries the provided logger.
+// Messages logged with the *Ctx functions (e.g. clio.InfoCtx) use this
+// logger rather than the global one.
+// Fields previously added with WithFields are retained.
+func WithContext(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	v := valueFromContext(ctx)
+	v.logger = logger
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// WithFields returns a copy of ctx with additional key-value pairs.
+// The fields are added to every message logged with the *Ctx functions,
+// after any fields already present in the context.
+func WithFields(ctx context.Context, keysAndValues ...any) context.Context {
+	v := valueFromContext(ctx)
+	// use a full slice expression so that sibling contexts don't share
+	// a backing array.
+	v.fields = append(v.fields[:len(v.fields):len(v.fields)], keysAndValues...)
+	return context.WithValue(ctx, contextKey{}, v)
+}
+
+// FromContext returns the logger attached to ctx with WithContext, including
+// any fields added with WithFields.
+// If no logger is attached, the global logger returned by S() is used.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	v := valueFromContext(ctx)
+	l := v.logger
+	if l == nil {
+		l = S()
+	}
+	if len(v.fields) > 0 {
+		l = l.With(v.fields...)
+	}
+	return l
+}
+
+func valueFromContext(ctx context.Context) contextValue {
+	if ctx == nil {
+		return contextValue{}
+	}
+	v, _ := ctx.Value(contextKey{}).(contextValue)
+	return v
+}
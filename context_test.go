@@ -0,0 +1,99 @@
+package clio
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+)
+
+func TestInfoCtx(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	ctx := WithFields(context.Background(), "profile", "dev")
+	ctx = WithFields(ctx, "account", "123")
+
+	InfoCtx(ctx, "my message")
+
+	got := b.String()
+
+	want := "[i] my message  \tprofile:dev\taccount:123\n"
+	if got != want {
+		t.Errorf("InfoCtx() = %q, want %q", got, want)
+	}
+}
+
+func TestInfoCtxWithoutLogger(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	InfoCtx(context.Background(), "my message")
+
+	got := b.String()
+
+	want := "[i] my message\n"
+	if got != want {
+		t.Errorf("InfoCtx() = %q, want %q", got, want)
+	}
+}
+
+func TestSuccessCtxNamedLogger(t *testing.T) {
+	var b bytes.Buffer
+	NoColor = true
+	logger := cliolog.New(zap.NewAtomicLevel(), cliolog.WithWriter(&b), cliolog.WithNoColor(&NoColor))
+
+	ctx := WithContext(context.Background(), logger.Sugar().Named("aws"))
+	ctx = WithFields(ctx, "command", "assume")
+
+	SuccessCtx(ctx, "my message")
+
+	got := b.String()
+
+	// the success indicator should be shown even though the logger in the
+	// context is already named.
+	want := "[✔] my message  \tcommand:assume\n"
+	if got != want {
+		t.Errorf("SuccessCtx() = %q, want %q", got, want)
+	}
+}
+
+func TestWithFieldsDoesNotShareFields(t *testing.T) {
+	parent := WithFields(context.Background(), "a", 1)
+	first := WithFields(parent, "b", 2)
+	second := WithFields(parent, "c", 3)
+
+	if got := valueFromContext(first).fields; len(got) != 4 || got[2] != "b" {
+		t.Errorf("first fields = %v", got)
+	}
+	if got := valueFromContext(second).fields; len(got) != 4 || got[2] != "c" {
+		t.Errorf("second fields = %v", got)
+	}
+}
+
+func TestTraceCtx(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetLevelFromString("trace")
+	defer restore()
+	// hide the location of trace messages.
+	defer Configure(cliolog.WithCaller(cliolog.CallerNever))()
+
+	ctx := WithFields(context.Background(), "profile", "dev")
+
+	TraceCtx(ctx, "my message")
+	TracewCtx(ctx, "my message", "key", "value")
+
+	got := b.String()
+
+	want := "[TRACE] my message  \tprofile:dev\n[TRACE] my message  \tkey:value\tprofile:dev\n"
+	if got != want {
+		t.Errorf("TraceCtx() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package clio
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/common-fate/clio/cliolog"
+)
+
+// Every returns a Logger which prints at most one message to the console
+// for each interval, from the line that it's called on. It's useful for
+// messages printed inside retry loops. Suppressed messages are still written
+// to the file log, if one is configured.
+//
+// Example:
+//
+//	for {
+//		clio.Every(5 * time.Second).Warnf("retrying: %s", err)
+//	}
+func Every(interval time.Duration) Logger {
+	return Logger{}.With(cliolog.RateLimit(callerKey(), interval))
+}
+
+// Every returns a copy of the Logger which prints at most one message to the
+// console for each interval, from the line that it's called on.
+func (l Logger) Every(interval time.Duration) Logger {
+	return l.With(cliolog.RateLimit(callerKey(), interval))
+}
+
+// callerKey returns the file and line that the function calling callerKey was called from.
+func callerKey() string {
+	_, file, line, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,102 @@
+package clio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEvery(t *testing.T) {
+	var console, file bytes.Buffer
+	NoColor = true
+	ws := zapcore.AddSync(&file)
+
+	restore := Configure(
+		cliolog.WithWriter(&console),
+		func(o *cliolog.Options) { o.FileWriteSyncer = &ws },
+	)
+	defer restore()
+
+	for i := 0; i < 3; i++ {
+		Every(time.Hour).Warnf("retrying %d", i)
+	}
+
+	if got, want := console.String(), "[!] retrying 0\n"; got != want {
+		t.Errorf("console = %q, want %q", got, want)
+	}
+	// the file log should still record every entry.
+	if got := file.String(); strings.Count(got, "retrying") != 3 || strings.Contains(got, "clio.ratelimit") {
+		t.Errorf("file = %q, want every entry without the rate limit field", got)
+	}
+}
+
+func TestDedup(t *testing.T) {
+	var b bytes.Buffer
+	NoColor = true
+
+	restore := Configure(cliolog.WithWriter(&b), cliolog.WithDedup())
+	defer restore()
+
+	Info("starting")
+	for i := 0; i < 13; i++ {
+		Warnf("retrying...")
+	}
+	Info("done")
+	Warn("retrying...")
+	Warn("retrying...")
+	_ = S().Sync()
+
+	got := b.String()
+
+	want := "[i] starting\n[!] retrying...\n[!] retrying... (repeated 12 times)\n[i] done\n[!] retrying...\n[!] retrying... (repeated 1 time)\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestDedupWithContext(t *testing.T) {
+	var b bytes.Buffer
+	NoColor = true
+
+	restore := Configure(cliolog.WithWriter(&b), cliolog.WithDedup())
+	defer restore()
+
+	S().With("account", "1").Info("ok")
+	S().With("account", "2").Info("ok")
+	S().With("account", "2").Info("ok")
+	_ = S().Sync()
+
+	got := b.String()
+
+	want := "[i] ok  \taccount:1\n[i] ok  \taccount:2\n[i] ok (repeated 1 time)  \taccount:2\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var b bytes.Buffer
+	NoColor = true
+
+	restore := Configure(cliolog.WithWriter(&b), cliolog.WithSampling(cliolog.SamplingConfig{Tick: time.Hour, First: 2, Thereafter: 100}))
+	defer restore()
+
+	restoreLevel := SetLevelFromString("debug")
+	defer restoreLevel()
+
+	for i := 0; i < 5; i++ {
+		Debug("flood")
+		Info("not sampled")
+	}
+
+	if got := strings.Count(b.String(), "[DEBUG] flood"); got != 2 {
+		t.Errorf("debug messages = %d, want 2", got)
+	}
+	if got := strings.Count(b.String(), "[i] not sampled"); got != 5 {
+		t.Errorf("info messages = %d, want 5", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/common-fate/clio"
+	"github.com/common-fate/clio/cliolog"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -15,9 +16,19 @@ func Example_levelFromEnv() {
 	// running CF_LOG=debug <your Go binary> will print debug logs.
 }
 
+// `clio.SetLevelFromEnv` also accepts per-subsystem levels. Loggers created
+// with `clio.Named()` or `clio.S().Named()` use the level set for their name.
+func Example_namedLevelFromEnv() {
+	clio.SetLevelFromEnv("CF_LOG")
+	// running CF_LOG=info,aws=debug <your Go binary> will print debug logs
+	// from clio.Named("aws"), and info logs from everything else.
+}
+
 // You can use `clio.Level.SetLevel()` to set the log level dynamically.
 func Example_dynamicLevel() {
 	clio.SetWriter(os.Stdout) // print to stdout just to show logs in the example.
+	// hide the location of debug messages.
+	defer clio.Configure(cliolog.WithCaller(cliolog.CallerNever))()
 
 	clio.Level.SetLevel(zapcore.InfoLevel)
 	clio.Debug("this isn't printed")
@@ -30,6 +41,8 @@ func Example_dynamicLevel() {
 // You can use `clio.SetLevelFromString()` to set the log level dynamically from a provided string.
 func Example_levelFromString() {
 	clio.SetWriter(os.Stdout) // print to stdout just to show logs in the example.
+	// hide the location of debug messages.
+	defer clio.Configure(cliolog.WithCaller(cliolog.CallerNever))()
 
 	clio.SetLevelFromString("info")
 	clio.Debug("this isn't printed")
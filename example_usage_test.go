@@ -4,6 +4,7 @@ import (
 	"os"
 
 	"github.com/common-fate/clio"
+	"github.com/common-fate/clio/cliolog"
 )
 
 // Here's the basics on how to use clio for logging.
@@ -12,6 +13,13 @@ func Example_usage() {
 
 	clio.SetWriter(os.Stdout) // print to stdout just to show logs in the example.
 
+	// the location of each message, and the stack trace of errors, are shown
+	// when debug logging is enabled. They're hidden here to keep the output short.
+	defer clio.Configure(func(o *cliolog.Options) {
+		o.Caller = cliolog.CallerNever
+		o.ErrorStacks = false
+	})()
+
 	// you can print basic logs like this
 	clio.Info("here's an info message")
 
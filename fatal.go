@@ -0,0 +1,74 @@
+package clio
+
+import (
+	"os"
+
+	"github.com/common-fate/clio/cliolog"
+)
+
+// ExitFunc is called by the Fatal functions to exit the program,
+// after the message has been printed and the logs have been flushed.
+// It can be replaced in tests to intercept the exit.
+var ExitFunc = os.Exit
+
+// Fatalf prints to stderr with a [✘] indicator, flushes and closes
+// the logs with Close, and then exits with status 1 by calling ExitFunc.
+// Fatal messages are logged at the error level with the cliolog.FatalKind
+// kind, so that they can be told apart from errors in the file log.
+func Fatalf(template string, args ...any) {
+	Emitf(cliolog.FatalKind, template, args...)
+	exit()
+}
+
+// Fatalln prints to stderr with a [✘] indicator using fmt.Sprintln, flushes and
+// closes the logs with Close, and then exits with status 1 by calling ExitFunc.
+func Fatalln(args ...any) {
+	Emitln(cliolog.FatalKind, args...)
+	exit()
+}
+
+// Fatal prints to stderr with a [✘] indicator using fmt.Sprint, flushes and
+// closes the logs with Close, and then exits with status 1 by calling ExitFunc.
+func Fatal(args ...any) {
+	Emit(cliolog.FatalKind, args...)
+	exit()
+}
+
+// Fatalw prints to stderr with a [✘] indicator and structured context, flushes
+// and closes the logs with Close, and then exits with status 1 by calling ExitFunc.
+func Fatalw(msg string, keysAndValues ...any) {
+	Emitw(cliolog.FatalKind, msg, keysAndValues...)
+	exit()
+}
+
+// exit closes the logs and calls ExitFunc.
+func exit() {
+	if err := Close(); err != nil {
+		// the file log couldn't be closed, so print the error to the console.
+		// Ignore errors, as we're about to exit anyway.
+		_, _ = os.Stderr.WriteString("error closing log file: " + err.Error() + "\n")
+	}
+	ExitFunc(1)
+}
+
+// Close flushes the global logger and closes the file log, if one is
+// configured with SetFileLogging. It should be called before the program
+// exits so that buffered messages, such as the summary of repeated messages,
+// are written.
+//
+// Errors syncing the console are ignored, as syncing terminals and pipes
+// commonly fails. The file log is reopened if messages are logged after
+// Close is called.
+func Close() error {
+	globalMu.RLock()
+	s := stderr
+	closer := globalOptions.FileCloser
+	globalMu.RUnlock()
+
+	_ = s.Sync()
+
+	if closer == nil {
+		return nil
+	}
+	return closer.Close()
+}
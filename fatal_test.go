@@ -0,0 +1,181 @@
+package clio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+)
+
+func TestFatal(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	var code int
+	ExitFunc = func(c int) { code = c }
+	defer func() { ExitFunc = os.Exit }()
+
+	restore := Configure(cliolog.WithDedup())
+	defer restore()
+
+	Error("retrying")
+	Error("retrying")
+	Fatalf("failed after %d attempts", 2)
+
+	want := "[✘] retrying\n[✘] retrying (repeated 1 time)\n[✘] failed after 2 attempts\n"
+	if got := b.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestFatalw(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	exited := false
+	ExitFunc = func(int) { exited = true }
+	defer func() { ExitFunc = os.Exit }()
+
+	Fatalw("failed", "error", "boom")
+
+	want := "[✘] failed  \terror:boom\n"
+	if got := b.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if !exited {
+		t.Error("ExitFunc was not called")
+	}
+}
+
+func TestFatalFileLog(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	ExitFunc = func(int) {}
+	defer func() { ExitFunc = os.Exit }()
+
+	path := filepath.Join(t.TempDir(), "cli.log")
+	restore := Configure(cliolog.WithFileLogger(cliolog.FileLoggerConfig{Filename: path}))
+	defer restore()
+
+	kv := make([]any, 2, 4)
+	kv[0], kv[1] = "error", "boom"
+	Fatalw("failed", kv...)
+
+	if got := kv[:cap(kv)][2]; got != nil {
+		t.Errorf("Fatalw wrote %v to the caller's key-value pairs", got)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"level":"error"`) || !strings.Contains(string(data), `"kind":"fatal"`) {
+		t.Errorf("file log = %q, want an error with the fatal kind", data)
+	}
+}
+
+func TestCloseFileLogging(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	path := filepath.Join(t.TempDir(), "cli.log")
+	restore := Configure(cliolog.WithFileLogger(cliolog.FileLoggerConfig{Filename: path}))
+	defer restore()
+
+	Info("before close")
+
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "before close") {
+		t.Errorf("file log = %q, want it to contain the message", data)
+	}
+
+	// the file log is reopened if messages are logged after closing.
+	Info("after close")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "after close") {
+		t.Errorf("file log = %q, want it to contain the message after reopening", data)
+	}
+}
+
+// closerFunc is an io.Closer which calls a function.
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+func TestConfigureClosesReplacedFile(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+
+	var closed []string
+	withCloser := func(name string) cliolog.Option {
+		c := closerFunc(func() error {
+			closed = append(closed, name)
+			return nil
+		})
+		return func(o *cliolog.Options) { o.FileCloser = &c }
+	}
+
+	restoreFirst := Configure(withCloser("first"))
+	restoreSecond := Configure(withCloser("second"))
+	if want := []string{"first"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+
+	// configuring other options keeps the file open.
+	SetWriter(&b)
+	if want := []string{"first"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+
+	restoreSecond()
+	restoreFirst()
+	if want := []string{"first", "second", "first"}; !reflect.DeepEqual(closed, want) {
+		t.Errorf("closed = %v, want %v", closed, want)
+	}
+}
+
+func TestConfigureNonComparableFile(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+
+	closed := false
+	// a func closer can't be compared, so it's never closed by Configure.
+	restore := Configure(func(o *cliolog.Options) {
+		o.FileCloser = closerFunc(func() error {
+			closed = true
+			return nil
+		})
+	})
+	SetWriter(&b)
+	restore()
+
+	if closed {
+		t.Error("a closer which can't be compared was closed")
+	}
+}
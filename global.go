@@ -3,10 +3,10 @@ package clio
 import (
 	"io"
 	"os"
+	"reflect"
 	"sync"
 
 	"github.com/common-fate/clio/cliolog"
-	"github.com/mattn/go-colorable"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -14,15 +14,18 @@ import (
 // SetLevelFromEnv configures the global logging level based on the provided
 // environment variables.
 // The env vars should be provided in priority order.
-// It returns a function which restores the previous log level.
+//
+// As well as a single level such as 'debug', the env vars may contain a
+// per-subsystem spec such as 'info,aws=debug,keychain=warn', which sets the
+// level for loggers named with S().Named("aws") and S().Named("keychain").
+// See cliolog.ParseLevelSpec for details.
+//
+// It returns a function which restores the previous log levels.
 func SetLevelFromEnv(vars ...string) func() {
 	for _, e := range vars {
 		val := os.Getenv(e)
-		lvl, err := zapcore.ParseLevel(val)
-		if err == nil {
-			old := Level.Level()
-			Level.SetLevel(lvl)
-			return func() { Level.SetLevel(old) }
+		if restore, ok := setLevelSpec(val); ok {
+			return restore
 		}
 	}
 	// if we get here, we couldn't parse any env vars.
@@ -31,15 +34,14 @@ func SetLevelFromEnv(vars ...string) func() {
 }
 
 // SetLevelFromString configures the global logging level based on the provided
-// string. Under the hood it uses zapcore.Parse() to try and parse the log level.
+// string. Under the hood it uses cliolog.ParseLevel() to try and parse the log level,
+// which supports the levels parsed by zapcore.ParseLevel() as well as 'trace'.
+// Per-subsystem specs such as 'info,aws=debug' are supported, in the same way as SetLevelFromEnv.
 // Does nothing if the log level can't be parsed.
-// It returns a function which restores the previous log level.
+// It returns a function which restores the previous log levels.
 func SetLevelFromString(level string) func() {
-	lvl, err := zapcore.ParseLevel(level)
-	if err == nil {
-		old := Level.Level()
-		Level.SetLevel(lvl)
-		return func() { Level.SetLevel(old) }
+	if restore, ok := setLevelSpec(level); ok {
+		return restore
 	}
 
 	// if we get here, we couldn't parse the level.
@@ -47,45 +49,201 @@ func SetLevelFromString(level string) func() {
 	return func() {}
 }
 
+// SetTimeFormatFromEnv configures how the time of each message is shown on the
+// console based on the provided environment variables, such as CF_LOG_TIME.
+// The env vars should be provided in priority order, and may contain none,
+// wall, elapsed or delta. See cliolog.WithTimeFormat for details.
+//
+// It returns a function which restores the previous time format.
+func SetTimeFormatFromEnv(vars ...string) func() {
+	for _, e := range vars {
+		val := os.Getenv(e)
+		if val == "" {
+			continue
+		}
+		if f, err := cliolog.ParseTimeFormat(val); err == nil {
+			return Configure(cliolog.WithTimeFormat(f))
+		}
+	}
+	// if we get here, we couldn't parse any env vars.
+	// Return a no-op func as we did nothing.
+	return func() {}
+}
+
+// SetNamedLevel sets the logging level for loggers with the provided name,
+// such as those created with S().Named(name) or clio.Named(name).
+// Loggers nested beneath the name, such as name.child, also use the level.
+// It returns a function which restores the previous log levels.
+func SetNamedLevel(name string, level zapcore.Level) func() {
+	old := namedLevels.Levels()
+	namedLevels.Set(name, level)
+	return func() { namedLevels.Replace(old) }
+}
+
+// setLevelSpec parses and applies a log level spec.
+// It returns false if the spec couldn't be parsed.
+func setLevelSpec(spec string) (restore func(), ok bool) {
+	ls, err := cliolog.ParseLevelSpec(spec)
+	if err != nil {
+		return nil, false
+	}
+
+	oldLevel := Level.Level()
+	oldNamed := namedLevels.Levels()
+
+	if ls.HasDefault {
+		Level.SetLevel(ls.Default)
+	}
+	for name, lvl := range ls.Named {
+		namedLevels.Set(name, lvl)
+	}
+
+	return func() {
+		Level.SetLevel(oldLevel)
+		namedLevels.Replace(oldNamed)
+	}, true
+}
+
 var (
 	// Level is the global logging level.
 	Level = zap.NewAtomicLevel()
 
+	// namedLevels holds the logging levels for named loggers.
+	// Unnamed loggers use Level.
+	namedLevels = cliolog.NewNamedLevels(Level)
+
 	// globalMu locks concurrent access to the global loggers.
 	globalMu sync.RWMutex
 
-	// errorWriter defaults to stderr
-	errorWriter = colorable.NewColorableStderr()
+	// globalOptions are the options used to build the global logger.
+	// The writer defaults to stderr, and the output writer defaults to stdout.
+	globalOptions = cliolog.Options{
+		Writer:       cliolog.NewColorable(os.Stderr),
+		OutputWriter: cliolog.NewColorable(os.Stdout),
+		NoColor:      &NoColor,
+		NamedLevels:  namedLevels,
+		Hooks:        []cliolog.Hook{runHooks},
+		Redactor:     redactor,
+		Caller:       cliolog.CallerAuto,
+		ErrorStacks:  true,
+	}
 
 	// stderr is a zap logger which writes to stderr
-	stderr = cliolog.New(
-		Level,
-		cliolog.WithWriter(errorWriter),
-		cliolog.WithNoColor(&NoColor),
-	).Sugar()
+	stderr = cliolog.New(Level, withOptions(globalOptions)).Sugar()
+
+	// replacement is the logger set with ReplaceGlobals, if any, which is
+	// used in place of a logger built from globalOptions.
+	replacement *zap.Logger
 )
 
+// Configure rebuilds the global zap logger, applying the provided options
+// on top of the current configuration. The console, file and extra writers
+// are built together while holding the lock, so options compose rather than
+// replacing each other: for example, calling Configure(cliolog.WithWriter(w))
+// after enabling file logging keeps the file logging. While a logger set with
+// ReplaceGlobals is in use, the options are applied once it's restored.
+//
+// It returns a function which restores the previous configuration.
+// Restore functions should be called in the reverse order to which they were returned.
+//
+// Example:
+//
+//	restore := clio.Configure(
+//		cliolog.WithFileLogger(cliolog.FileLoggerConfig{Filename: "cli.log"}),
+//		cliolog.WithExtraWriter(&buf),
+//	)
+//	defer restore()
+func Configure(opts ...cliolog.Option) (restore func()) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	prevOptions := globalOptions
+	prevLogger := stderr
+	prevReplacement := replacement
+
+	o := globalOptions
+	// copy the extra writers and hooks so that appending to them doesn't
+	// modify the previous configuration.
+	o.ExtraWriters = append([]io.Writer(nil), o.ExtraWriters...)
+	o.Hooks = append([]cliolog.Hook(nil), o.Hooks...)
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	replaced := replacedFile(prevOptions, o)
+	globalOptions = o
+	stderr = buildGlobal(o)
+	closeFile(replaced)
+
+	return func() {
+		globalMu.Lock()
+		defer globalMu.Unlock()
+
+		replaced := replacedFile(globalOptions, prevOptions)
+		globalOptions = prevOptions
+		if replacement == prevReplacement {
+			stderr = prevLogger
+		} else {
+			// ReplaceGlobals was called or restored since Configure, so the
+			// previous logger may no longer be the right one to use.
+			stderr = buildGlobal(prevOptions)
+		}
+		closeFile(replaced)
+	}
+}
+
+// replacedFile returns the file log of prev if it isn't used by next, so
+// that replacing the file logger doesn't leak open files. The file is
+// reopened if the previous configuration is restored and written to.
+//
+// Closers which can't be compared, such as funcs, are never returned, as
+// it isn't possible to tell whether they are still in use.
+func replacedFile(prev, next cliolog.Options) io.Closer {
+	if prev.FileCloser == nil {
+		return nil
+	}
+	if !reflect.TypeOf(prev.FileCloser).Comparable() || prev.FileCloser == next.FileCloser {
+		return nil
+	}
+	return prev.FileCloser
+}
+
+// closeFile closes a file log returned by replacedFile, if there is one.
+func closeFile(c io.Closer) {
+	if c == nil {
+		return
+	}
+	// ignore errors, as there's nowhere to report them.
+	_ = c.Close()
+}
+
+// withOptions returns an option which replaces all options with o.
+func withOptions(o cliolog.Options) cliolog.Option {
+	return func(opts *cliolog.Options) {
+		*opts = o
+	}
+}
+
 // SetWriter rebuilds the global zap logger with a specific writer.
 // All Info, Error, Warn, Debug, etc messages are sent here.
-// clio.Log messages are sent to stdout.
+// clio.Log messages are sent to stdout, which can be changed with SetOutputWriter.
+// File logging configured with SetFileLogging is retained.
 func SetWriter(w io.Writer) {
-	globalMu.Lock()
-	defer globalMu.Unlock()
+	Configure(cliolog.WithWriter(w))
+}
 
-	stderr = cliolog.New(
-		Level,
-		cliolog.WithWriter(w),
-		cliolog.WithNoColor(&NoColor),
-	).Sugar()
+// SetOutputWriter rebuilds the global zap logger with a specific output writer.
+// clio.Log messages are sent here, and it defaults to stdout.
+// Status messages such as Info, Error, Warn and Debug messages are sent to
+// the writer configured with SetWriter, so that output can be piped safely.
+func SetOutputWriter(w io.Writer) {
+	Configure(cliolog.WithOutputWriter(w))
 }
 
+// SetFileLogging rebuilds the global zap logger to write logs to a file
+// in addition to the console.
 func SetFileLogging(fcfg cliolog.FileLoggerConfig) {
-	stderr = cliolog.New(
-		Level,
-		cliolog.WithFileLogger(fcfg),
-		cliolog.WithWriter(errorWriter),
-		cliolog.WithNoColor(&NoColor),
-	).Sugar()
+	Configure(cliolog.WithFileLogger(fcfg))
 }
 
 // G returns the global stderr logger
@@ -108,10 +266,83 @@ func S() *zap.SugaredLogger {
 
 // ReplaceGlobals replaces the global Logger and SugaredLogger, and returns a
 // function to restore the original values. It's safe for concurrent use.
+//
+// The replacement logger is used as-is, so it isn't affected by the options
+// set with Configure. Options set with Configure (or SetWriter, SetFileLogging,
+// etc) while the replacement is in use are kept, and the global logger is
+// built from them when the restore function is called.
 func ReplaceGlobals(logger *zap.Logger) func() {
 	globalMu.Lock()
-	prev := stderr.Desugar()
+	defer globalMu.Unlock()
+
+	prev := replacement
+	replacement = logger
 	stderr = logger.Sugar()
-	globalMu.Unlock()
-	return func() { ReplaceGlobals(prev) }
+
+	return func() {
+		globalMu.Lock()
+		defer globalMu.Unlock()
+
+		replacement = prev
+		stderr = buildGlobal(globalOptions)
+	}
+}
+
+// buildGlobal returns the global logger for the options: the replacement
+// logger if ReplaceGlobals has been called, or a logger built from o.
+// globalMu must be held.
+func buildGlobal(o cliolog.Options) *zap.SugaredLogger {
+	if replacement != nil {
+		return replacement.Sugar()
+	}
+	return cliolog.New(Level, withOptions(o)).Sugar()
+}
+
+// globalCore is a zapcore.Core which writes to the global logger.
+// The global logger is resolved on each call, so that changes made
+// with Configure or SetWriter are picked up, including by cores
+// created with With.
+type globalCore struct {
+	// fields are the fields added with With.
+	fields []zapcore.Field
+}
+
+// core returns the global logger's core with the fields added.
+func (c globalCore) core() zapcore.Core {
+	core := G().Core()
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+	return core
+}
+
+// Enabled implements zapcore.Core
+func (globalCore) Enabled(l zapcore.Level) bool {
+	return G().Core().Enabled(l)
+}
+
+// With implements zapcore.Core
+func (c globalCore) With(fields []zapcore.Field) zapcore.Core {
+	// use a full slice expression so that sibling cores don't share
+	// a backing array.
+	return globalCore{fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+// Check implements zapcore.Core
+func (c globalCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	// check the level before adding the fields to the core.
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	return c.core().Check(ent, ce)
+}
+
+// Write implements zapcore.Core
+func (c globalCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.core().Write(ent, fields)
+}
+
+// Sync implements zapcore.Core
+func (globalCore) Sync() error {
+	return G().Core().Sync()
 }
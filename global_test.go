@@ -0,0 +1,130 @@
+package clio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetLevelFromEnvNamedLevels(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	t.Setenv("CF_LOG", "info,aws=debug,keychain=warn")
+	restore := SetLevelFromEnv("CF_LOG")
+	defer restore()
+
+	// hide the location of debug messages.
+	defer Configure(cliolog.WithCaller(cliolog.CallerNever))()
+
+	S().Named("aws").Debug("aws debug")
+	S().Named("aws").Named("sso").Debug("aws sso debug")
+	S().Named("keychain").Info("keychain info")
+	S().Named("keychain").Warn("keychain warn")
+	Debug("default debug")
+	Info("default info")
+
+	got := b.String()
+
+	want := "[DEBUG] aws debug\n[DEBUG] aws sso debug\n[!] keychain warn\n[i] default info\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSetNamedLevel(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetNamedLevel("http", zapcore.ErrorLevel)
+
+	Named("http").Warn("hidden")
+	Named("http").Error("shown")
+
+	restore()
+
+	Named("http").Warn("shown after restore")
+
+	got := b.String()
+
+	want := "[✘] shown\n[!] shown after restore\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSetLevelFromStringInvalidSpec(t *testing.T) {
+	old := Level.Level()
+
+	SetLevelFromString("info,aws=nope")()
+
+	if Level.Level() != old {
+		t.Errorf("level = %s, want %s", Level.Level(), old)
+	}
+}
+
+func TestConfigureComposes(t *testing.T) {
+	var console, file, extra bytes.Buffer
+	NoColor = true
+	ws := zapcore.AddSync(&file)
+
+	restoreFile := Configure(func(o *cliolog.Options) { o.FileWriteSyncer = &ws })
+	defer restoreFile()
+
+	// redirecting the console shouldn't discard the file logging.
+	SetWriter(&console)
+
+	restoreExtra := Configure(cliolog.WithExtraWriter(&extra))
+
+	Info("my message")
+
+	restoreExtra()
+
+	Info("my other message")
+
+	if got, want := console.String(), "[i] my message\n[i] my other message\n"; got != want {
+		t.Errorf("console = %q, want %q", got, want)
+	}
+	if got, want := extra.String(), "[i] my message\n"; got != want {
+		t.Errorf("extra = %q, want %q", got, want)
+	}
+	if got := file.String(); strings.Count(got, `"msg":`) != 2 {
+		t.Errorf("file = %q, want both messages", got)
+	}
+}
+
+func TestReplaceGlobalsConfigure(t *testing.T) {
+	var before, after bytes.Buffer
+	SetWriter(&before)
+	NoColor = true
+
+	observed, logs := observer.New(zapcore.InfoLevel)
+	restoreGlobals := ReplaceGlobals(zap.New(observed))
+
+	// options set while the replacement is in use don't discard it.
+	restore := Configure(cliolog.WithWriter(&after))
+	defer restore()
+
+	Info("replaced")
+	if logs.Len() != 1 {
+		t.Errorf("replacement logger received %d messages, want 1", logs.Len())
+	}
+
+	// the options are applied once the replacement is restored.
+	restoreGlobals()
+	Info("configured")
+
+	if got := before.String(); got != "" {
+		t.Errorf("previous writer = %q, want no output", got)
+	}
+	if got, want := after.String(), "[i] configured\n"; got != want {
+		t.Errorf("configured writer = %q, want %q", got, want)
+	}
+}
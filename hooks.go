@@ -0,0 +1,145 @@
+package clio
+
+import (
+	"sync"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap/zapcore"
+)
+
+// MessageCounts are the number of messages logged at each level, and of each kind,
+// since the program started or since ResetCounts was called.
+// Messages are only counted if they're written to the console or file log.
+type MessageCounts struct {
+	Trace int
+	Debug int
+	Info  int
+	Warn  int
+	// Error includes messages at the error level and above.
+	Error int
+	// Kinds are the number of messages of each kind, such as "success".
+	Kinds map[string]int
+}
+
+// Success returns the number of success messages.
+func (c MessageCounts) Success() int {
+	return c.Kinds[cliolog.SuccessKind]
+}
+
+// hook is a registered hook. It's a pointer so that it can be removed.
+type hook struct {
+	fn func(zapcore.Entry, []zapcore.Field)
+}
+
+var (
+	// hooksMu locks access to hooks.
+	hooksMu sync.RWMutex
+	hooks   []*hook
+
+	// countsMu locks access to counts.
+	countsMu sync.Mutex
+	counts   = MessageCounts{Kinds: map[string]int{}}
+)
+
+// AddHook adds a function which is called for each message written by
+// the global logger. The fields include those added to the logger with With().
+// Hooks are called synchronously, so they should be fast and must not log.
+//
+// It returns a function which removes the hook.
+//
+// Example:
+//
+//	clio.AddHook(func(ent zapcore.Entry, fields []zapcore.Field) {
+//		if ent.Level >= zapcore.ErrorLevel {
+//			exitCode = 1
+//		}
+//	})
+func AddHook(fn func(zapcore.Entry, []zapcore.Field)) func() {
+	h := &hook{fn: fn}
+
+	hooksMu.Lock()
+	hooks = append(hooks, h)
+	hooksMu.Unlock()
+
+	return func() {
+		hooksMu.Lock()
+		defer hooksMu.Unlock()
+		for i := range hooks {
+			if hooks[i] == h {
+				hooks = append(hooks[:i:i], hooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Counts returns the number of messages logged at each level, and of each kind,
+// since the program started or since ResetCounts was called.
+//
+// Example:
+//
+//	c := clio.Counts()
+//	clio.Infof("finished with %d warnings", c.Warn)
+func Counts() MessageCounts {
+	countsMu.Lock()
+	defer countsMu.Unlock()
+
+	c := counts
+	c.Kinds = make(map[string]int, len(counts.Kinds))
+	for k, v := range counts.Kinds {
+		c.Kinds[k] = v
+	}
+	return c
+}
+
+// ResetCounts resets the counts returned by Counts to zero.
+func ResetCounts() {
+	countsMu.Lock()
+	counts = MessageCounts{Kinds: map[string]int{}}
+	countsMu.Unlock()
+}
+
+// runHooks is the hook installed on the global logger.
+// It updates the counts and then calls the hooks added with AddHook.
+func runHooks(ent zapcore.Entry, fields []zapcore.Field) {
+	count(ent, fields)
+
+	hooksMu.RLock()
+	hs := hooks
+	hooksMu.RUnlock()
+
+	for _, h := range hs {
+		h.fn(ent, fields)
+	}
+}
+
+// count increments the counts for an entry.
+func count(ent zapcore.Entry, fields []zapcore.Field) {
+	countsMu.Lock()
+	defer countsMu.Unlock()
+
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		counts.Error++
+	case ent.Level == zapcore.WarnLevel:
+		counts.Warn++
+	case ent.Level == zapcore.InfoLevel:
+		counts.Info++
+	case ent.Level == zapcore.DebugLevel:
+		counts.Debug++
+	default:
+		counts.Trace++
+	}
+
+	// the last kind field takes precedence, as entry fields come after
+	// the fields added with With().
+	kind := ""
+	for _, f := range fields {
+		if f.Key == cliolog.KindKey && f.Type == zapcore.StringType {
+			kind = f.String
+		}
+	}
+	if kind != "" {
+		counts.Kinds[kind]++
+	}
+}
@@ -0,0 +1,64 @@
+package clio
+
+import (
+	"bytes"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCounts(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+	ResetCounts()
+
+	restoreLevel := SetLevelFromString("info")
+	defer restoreLevel()
+
+	Info("info")
+	Success("success")
+	Named("aws").Successf("success")
+	Warn("warn")
+	Warnf("warn")
+	Error("error")
+	// debug messages aren't written, so they aren't counted.
+	Debug("debug")
+
+	c := Counts()
+
+	if c.Info != 3 || c.Warn != 2 || c.Error != 1 || c.Debug != 0 || c.Success() != 2 {
+		t.Errorf("Counts() = %+v", c)
+	}
+
+	ResetCounts()
+
+	if c := Counts(); c.Info != 0 || c.Success() != 0 {
+		t.Errorf("Counts() after reset = %+v", c)
+	}
+}
+
+func TestAddHook(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	var got []string
+	remove := AddHook(func(ent zapcore.Entry, fields []zapcore.Field) {
+		msg := ent.Message
+		for _, f := range fields {
+			msg += " " + f.Key + "=" + f.String
+		}
+		got = append(got, msg)
+	})
+
+	With("profile", "dev").Errorw("my error", "account", "123")
+
+	remove()
+
+	Error("not hooked")
+
+	if len(got) != 1 || got[0] != "my error profile=dev account=123" {
+		t.Errorf("hooked entries = %q", got)
+	}
+}
@@ -0,0 +1,119 @@
+package clio
+
+import (
+	"fmt"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Emitf prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprintf.
+// The message is shown with the kind's symbol and color, and logged at the kind's level.
+// Unregistered kinds are logged at the info level.
+func Emitf(kind string, template string, args ...any) {
+	Logger{}.Emitf(kind, template, args...)
+}
+
+// Emitln prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprintln.
+func Emitln(kind string, args ...any) {
+	Logger{}.Emitln(kind, args...)
+}
+
+// Emit prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprint.
+func Emit(kind string, args ...any) {
+	Logger{}.Emit(kind, args...)
+}
+
+// Emitw prints a message of a kind registered with cliolog.RegisterKind with additional key-value pairs.
+func Emitw(kind string, msg string, keysAndValues ...any) {
+	Logger{}.Emitw(kind, msg, keysAndValues...)
+}
+
+// Emitf prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprintf.
+func (l Logger) Emitf(kind string, template string, args ...any) {
+	s, lvl := l.kind(kind)
+	if enabled(s, lvl) {
+		s.Desugar().Log(lvl, fmt.Sprintf(template, args...))
+	}
+}
+
+// Emitln prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprintln.
+func (l Logger) Emitln(kind string, args ...any) {
+	s, lvl := l.kind(kind)
+	if enabled(s, lvl) {
+		s.Desugar().Log(lvl, sprintln(args...))
+	}
+}
+
+// Emit prints a message of a kind registered with cliolog.RegisterKind using fmt.Sprint.
+func (l Logger) Emit(kind string, args ...any) {
+	s, lvl := l.kind(kind)
+	if enabled(s, lvl) {
+		s.Desugar().Log(lvl, fmt.Sprint(args...))
+	}
+}
+
+// Emitw prints a message of a kind registered with cliolog.RegisterKind with additional key-value pairs.
+func (l Logger) Emitw(kind string, msg string, keysAndValues ...any) {
+	s, lvl := l.kind(kind)
+	if enabled(s, lvl) {
+		s.Desugar().Log(lvl, msg, sweetenFields(keysAndValues)...)
+	}
+}
+
+// kind returns the logger to use for messages of the provided kind,
+// and the level to log them at.
+func (l Logger) kind(kind string) (*zap.SugaredLogger, zapcore.Level) {
+	lvl := zapcore.InfoLevel
+	if k, ok := cliolog.LookupKind(kind); ok {
+		lvl = k.Level
+	}
+	return withKind(l.Sugar(), kind), lvl
+}
+
+// withKind returns a logger which marks messages as being of the provided kind.
+func withKind(s *zap.SugaredLogger, kind string) *zap.SugaredLogger {
+	return s.With(cliolog.KindField(kind))
+}
+
+// enabled returns true if the logger will write messages at the provided level.
+// The sugared logger doesn't have methods which take a dynamic level, so this
+// is used to avoid formatting messages which won't be written.
+func enabled(s *zap.SugaredLogger, lvl zapcore.Level) bool {
+	return s.Desugar().Core().Enabled(lvl)
+}
+
+// sweetenFields converts key-value pairs to fields, in the same way as the
+// zap sugared logger's 'w' methods. Fields in the list are used as-is.
+// Keys which aren't strings, and a final key without a value, are added
+// with the key "!BADKEY" rather than being dropped.
+func sweetenFields(keysAndValues []any) []zap.Field {
+	fields := make([]zap.Field, 0, len(keysAndValues)/2)
+	for i := 0; i < len(keysAndValues); i++ {
+		switch key := keysAndValues[i].(type) {
+		case zap.Field:
+			fields = append(fields, key)
+		case string:
+			if i+1 == len(keysAndValues) {
+				fields = append(fields, zap.String(badKey, key))
+				continue
+			}
+			fields = append(fields, zap.Any(key, keysAndValues[i+1]))
+			i++
+		default:
+			fields = append(fields, zap.Any(badKey, key))
+		}
+	}
+	return fields
+}
+
+// badKey is the key of values in key-value pairs which don't have a valid key.
+const badKey = "!BADKEY"
+
+// sprintln formats a message in the same way as the zap sugared logger's
+// 'ln' methods, using fmt.Sprintln without the trailing newline.
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,87 @@
+package clio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/common-fate/clio/ansi"
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestEmit(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	cliolog.RegisterKind("tip", "TIP", ansi.Cyan, zapcore.InfoLevel)
+
+	Emitf("tip", "run %s to get started", "init")
+
+	got := b.String()
+
+	want := "[TIP] run init to get started\n"
+	if got != want {
+		t.Errorf("Emitf() = %q, want %q", got, want)
+	}
+}
+
+func TestSetKindEnabled(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	cliolog.RegisterKind("notice", "NOTICE", ansi.Cyan, zapcore.InfoLevel)
+	cliolog.SetKindEnabled("notice", false)
+
+	Emit("notice", "my message")
+	Info("my other message")
+
+	got := b.String()
+
+	want := "[i] my other message\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestSuccessFileLogIncludesKind(t *testing.T) {
+	var console, file bytes.Buffer
+	NoColor = true
+	ws := zapcore.AddSync(&file)
+	logger := cliolog.New(
+		zap.NewAtomicLevel(),
+		cliolog.WithWriter(&console),
+		cliolog.WithNoColor(&NoColor),
+		func(o *cliolog.Options) { o.FileWriteSyncer = &ws },
+	)
+
+	NewLogger(logger.Sugar()).Successw("my message", "key", "value")
+
+	if got, want := console.String(), "[✔] my message  \tkey:value\n"; got != want {
+		t.Errorf("console output = %q, want %q", got, want)
+	}
+	if got := file.String(); !strings.Contains(got, `"kind":"success"`) || !strings.Contains(got, `"level":"info"`) {
+		t.Errorf("file output = %q, want the kind field", got)
+	}
+}
+
+func TestEmitwFieldOrder(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	cliolog.RegisterKind("tip", "TIP", ansi.Cyan, zapcore.InfoLevel)
+
+	// per-call fields are shown before the logger's fields, as with Infow.
+	With("profile", "dev").Emitw("tip", "my message", "key", "value", "dangling")
+
+	got := b.String()
+
+	want := "[TIP] my message  \tkey:value\t!BADKEY:dangling\tprofile:dev\n"
+	if got != want {
+		t.Errorf("Emitw() = %q, want %q", got, want)
+	}
+}
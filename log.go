@@ -2,10 +2,12 @@
 package clio
 
 import (
+	"context"
+
 	"github.com/common-fate/clio/cliolog"
 )
 
-// Log prints to stderr with no prefix.
+// Logf prints to stdout with no prefix.
 func Logf(template string, args ...any) {
 	S().Named(cliolog.NoPrefixName).Infof(template, args...)
 }
@@ -17,7 +19,7 @@ func Infof(template string, args ...interface{}) {
 
 // Success prints to stderr with a [✔] indicator.
 func Successf(template string, args ...interface{}) {
-	S().Named(cliolog.SuccessName).Infof(template, args...)
+	withKind(S(), cliolog.SuccessKind).Infof(template, args...)
 }
 
 // Error prints to stderr with a [✘] indicator.
@@ -36,7 +38,13 @@ func Debugf(template string, args ...any) {
 	S().Debugf(template, args...)
 }
 
-// Logln prints with no prefix using fmt.Sprintln.
+// Tracef prints to stderr with a [TRACE] indicator.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func Tracef(template string, args ...any) {
+	Logger{}.Tracef(template, args...)
+}
+
+// Logln prints to stdout with no prefix using fmt.Sprintln.
 func Logln(args ...any) {
 	S().Named(cliolog.NoPrefixName).Infoln(args...)
 }
@@ -48,7 +56,7 @@ func Infoln(args ...any) {
 
 // Successln prints to stderr with a [✔] indicator using fmt.Sprintln.
 func Successln(args ...any) {
-	S().Named(cliolog.SuccessName).Infoln(args...)
+	withKind(S(), cliolog.SuccessKind).Infoln(args...)
 }
 
 // Errorln prints to stderr with a [✘] indicator using fmt.Sprintln.
@@ -67,7 +75,13 @@ func Debugln(args ...any) {
 	S().Debugln(args...)
 }
 
-// Log uses fmt.Sprint to construct and log a message.
+// Traceln prints to stderr with a [TRACE] indicator using fmt.Sprintln.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func Traceln(args ...any) {
+	Logger{}.Traceln(args...)
+}
+
+// Log prints to stdout with no prefix using fmt.Sprint.
 func Log(args ...any) {
 	S().Named(cliolog.NoPrefixName).Info(args...)
 }
@@ -79,7 +93,7 @@ func Info(args ...any) {
 
 // Success prints to stderr with a [✔] indicator using fmt.Sprint.
 func Success(args ...any) {
-	S().Named(cliolog.SuccessName).Info(args...)
+	withKind(S(), cliolog.SuccessKind).Info(args...)
 }
 
 // Error prints to stderr with a [✘] indicator using fmt.Sprint.
@@ -98,6 +112,12 @@ func Debug(args ...any) {
 	S().Debug(args...)
 }
 
+// Trace prints to stderr with a [TRACE] indicator using fmt.Sprint.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func Trace(args ...any) {
+	Logger{}.Trace(args...)
+}
+
 // Infow prints to stderr with an [i] indicator with additional key-value pairs.
 func Infow(msg string, keysAndValues ...any) {
 	S().Infow(msg, keysAndValues...)
@@ -105,7 +125,7 @@ func Infow(msg string, keysAndValues ...any) {
 
 // Success prints to stderr with a [✔] indicator with additional key-value pairs.
 func Successw(msg string, keysAndValues ...any) {
-	S().Named(cliolog.SuccessName).Infow(msg, keysAndValues...)
+	withKind(S(), cliolog.SuccessKind).Infow(msg, keysAndValues...)
 }
 
 // Error prints to stderr with a [✘] indicator with additional key-value pairs.
@@ -123,3 +143,161 @@ func Warnw(msg string, keysAndValues ...any) {
 func Debugw(msg string, keysAndValues ...any) {
 	S().Debugw(msg, keysAndValues...)
 }
+
+// Tracew prints to stderr with a [TRACE] indicator with additional key-value pairs.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func Tracew(msg string, keysAndValues ...any) {
+	Logger{}.Tracew(msg, keysAndValues...)
+}
+
+// InfoCtx prints to stderr with an [i] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func InfoCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Info(args...)
+}
+
+// InfofCtx prints to stderr with an [i] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func InfofCtx(ctx context.Context, template string, args ...any) {
+	FromContext(ctx).Infof(template, args...)
+}
+
+// InfolnCtx prints to stderr with an [i] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func InfolnCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Infoln(args...)
+}
+
+// InfowCtx prints to stderr with an [i] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func InfowCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).Infow(msg, keysAndValues...)
+}
+
+// SuccessCtx prints to stderr with a [✔] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func SuccessCtx(ctx context.Context, args ...any) {
+	withKind(FromContext(ctx), cliolog.SuccessKind).Info(args...)
+}
+
+// SuccessfCtx prints to stderr with a [✔] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func SuccessfCtx(ctx context.Context, template string, args ...any) {
+	withKind(FromContext(ctx), cliolog.SuccessKind).Infof(template, args...)
+}
+
+// SuccesslnCtx prints to stderr with a [✔] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func SuccesslnCtx(ctx context.Context, args ...any) {
+	withKind(FromContext(ctx), cliolog.SuccessKind).Infoln(args...)
+}
+
+// SuccesswCtx prints to stderr with a [✔] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func SuccesswCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	withKind(FromContext(ctx), cliolog.SuccessKind).Infow(msg, keysAndValues...)
+}
+
+// ErrorCtx prints to stderr with a [✘] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func ErrorCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Error(args...)
+}
+
+// ErrorfCtx prints to stderr with a [✘] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func ErrorfCtx(ctx context.Context, template string, args ...any) {
+	FromContext(ctx).Errorf(template, args...)
+}
+
+// ErrorlnCtx prints to stderr with a [✘] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func ErrorlnCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Errorln(args...)
+}
+
+// ErrorwCtx prints to stderr with a [✘] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func ErrorwCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).Errorw(msg, keysAndValues...)
+}
+
+// WarnCtx prints to stderr with a [!] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func WarnCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Warn(args...)
+}
+
+// WarnfCtx prints to stderr with a [!] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func WarnfCtx(ctx context.Context, template string, args ...any) {
+	FromContext(ctx).Warnf(template, args...)
+}
+
+// WarnlnCtx prints to stderr with a [!] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func WarnlnCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Warnln(args...)
+}
+
+// WarnwCtx prints to stderr with a [!] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+func WarnwCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).Warnw(msg, keysAndValues...)
+}
+
+// DebugCtx prints to stderr with a [DEBUG] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func DebugCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Debug(args...)
+}
+
+// DebugfCtx prints to stderr with a [DEBUG] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func DebugfCtx(ctx context.Context, template string, args ...any) {
+	FromContext(ctx).Debugf(template, args...)
+}
+
+// DebuglnCtx prints to stderr with a [DEBUG] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func DebuglnCtx(ctx context.Context, args ...any) {
+	FromContext(ctx).Debugln(args...)
+}
+
+// DebugwCtx prints to stderr with a [DEBUG] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func DebugwCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	FromContext(ctx).Debugw(msg, keysAndValues...)
+}
+
+// TraceCtx prints to stderr with a [TRACE] indicator using fmt.Sprint.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func TraceCtx(ctx context.Context, args ...any) {
+	NewLogger(FromContext(ctx)).Trace(args...)
+}
+
+// TracefCtx prints to stderr with a [TRACE] indicator using fmt.Sprintf.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func TracefCtx(ctx context.Context, template string, args ...any) {
+	NewLogger(FromContext(ctx)).Tracef(template, args...)
+}
+
+// TracelnCtx prints to stderr with a [TRACE] indicator using fmt.Sprintln.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func TracelnCtx(ctx context.Context, args ...any) {
+	NewLogger(FromContext(ctx)).Traceln(args...)
+}
+
+// TracewCtx prints to stderr with a [TRACE] indicator with additional key-value pairs.
+// The logger and fields attached to ctx are used, falling back to the global logger.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func TracewCtx(ctx context.Context, msg string, keysAndValues ...any) {
+	NewLogger(FromContext(ctx)).Tracew(msg, keysAndValues...)
+}
@@ -3,6 +3,8 @@ package clio
 import (
 	"bytes"
 	"testing"
+
+	"github.com/common-fate/clio/cliolog"
 )
 
 func TestInfo(t *testing.T) {
@@ -36,17 +38,84 @@ func TestError(t *testing.T) {
 }
 
 func TestLog(t *testing.T) {
-	var b bytes.Buffer
-	SetWriter(&b)
+	var stdout, stderr bytes.Buffer
+	SetWriter(&stderr)
+	SetOutputWriter(&stdout)
 	NoColor = true
 
 	Log("my message")
 
-	got := b.String()
+	got := stdout.String()
 
 	// should print without a prefix.
 	want := "my message\n"
 	if got != want {
 		t.Errorf("Log() = %q, want %q", got, want)
 	}
+	if stderr.Len() != 0 {
+		t.Errorf("Log() wrote %q to stderr", stderr.String())
+	}
+}
+
+func TestOutputAndStatusSplit(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	SetWriter(&stderr)
+	SetOutputWriter(&stdout)
+	NoColor = true
+
+	Info("fetching accounts")
+	Logf("account-%d", 1)
+	Named("aws").Logln("account-2")
+	Warn("account-3 is disabled")
+
+	if got, want := stdout.String(), "account-1\naccount-2\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "[i] fetching accounts\n[!] account-3 is disabled\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	t.Setenv("CF_LOG", "trace")
+	restore := SetLevelFromEnv("CF_LOG")
+	defer restore()
+
+	// hide the location of debug messages.
+	defer Configure(cliolog.WithCaller(cliolog.CallerNever))()
+
+	Tracew("my message", "key", "value")
+
+	got := b.String()
+
+	want := "[TRACE] my message  \tkey:value\n"
+	if got != want {
+		t.Errorf("Tracew() = %q, want %q", got, want)
+	}
+}
+
+func TestTraceNotShownAtDebug(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetLevelFromString("debug")
+	defer restore()
+
+	// hide the location of debug messages.
+	defer Configure(cliolog.WithCaller(cliolog.CallerNever))()
+
+	Tracef("my %s", "message")
+	Debug("my message")
+
+	got := b.String()
+
+	want := "[DEBUG] my message\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
 }
@@ -0,0 +1,229 @@
+package clio
+
+import (
+	"fmt"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+)
+
+// Logger is a scoped logger which has the same Log, Info, Success, Error,
+// Warn, Debug and Trace methods as the clio package.
+// Subsystems can be handed a Logger rather than calling the global functions.
+//
+// The zero value is ready to use and logs to the global logger.
+// The global logger is resolved each time a message is logged, so a Logger
+// created before calling SetWriter will use the new writer.
+type Logger struct {
+	// base is the logger to use. If nil, the global logger is used.
+	base   *zap.SugaredLogger
+	name   string
+	fields []any
+}
+
+// With returns a Logger which adds the key-value pairs to every message.
+func With(keysAndValues ...any) Logger {
+	return Logger{}.With(keysAndValues...)
+}
+
+// Named returns a Logger with the provided name.
+func Named(name string) Logger {
+	return Logger{}.Named(name)
+}
+
+// NewLogger returns a Logger which writes to the provided zap logger
+// rather than the global logger.
+func NewLogger(logger *zap.SugaredLogger) Logger {
+	return Logger{base: logger}
+}
+
+// With returns a copy of the Logger which adds the key-value pairs to every message.
+func (l Logger) With(keysAndValues ...any) Logger {
+	// use a full slice expression so that sibling loggers don't share
+	// a backing array.
+	l.fields = append(l.fields[:len(l.fields):len(l.fields)], keysAndValues...)
+	return l
+}
+
+// Named returns a copy of the Logger with name appended to its name.
+// Names are joined with a period, in the same way as zap.Logger.Named.
+func (l Logger) Named(name string) Logger {
+	switch {
+	case name == "":
+	case l.name == "":
+		l.name = name
+	default:
+		l.name = l.name + "." + name
+	}
+	return l
+}
+
+// Sugar returns the Logger as a sugared zap logger.
+func (l Logger) Sugar() *zap.SugaredLogger {
+	s := l.base
+	if s == nil {
+		s = S()
+	}
+	if l.name != "" {
+		s = s.Named(l.name)
+	}
+	if len(l.fields) > 0 {
+		s = s.With(l.fields...)
+	}
+	return s
+}
+
+// Logf prints to stdout with no prefix.
+func (l Logger) Logf(template string, args ...any) {
+	l.Sugar().Named(cliolog.NoPrefixName).Infof(template, args...)
+}
+
+// Infof prints to stderr with an [i] indicator using fmt.Sprintf.
+func (l Logger) Infof(template string, args ...any) {
+	l.Sugar().Infof(template, args...)
+}
+
+// Successf prints to stderr with a [✔] indicator using fmt.Sprintf.
+func (l Logger) Successf(template string, args ...any) {
+	withKind(l.Sugar(), cliolog.SuccessKind).Infof(template, args...)
+}
+
+// Errorf prints to stderr with a [✘] indicator using fmt.Sprintf.
+func (l Logger) Errorf(template string, args ...any) {
+	l.Sugar().Errorf(template, args...)
+}
+
+// Warnf prints to stderr with a [!] indicator using fmt.Sprintf.
+func (l Logger) Warnf(template string, args ...any) {
+	l.Sugar().Warnf(template, args...)
+}
+
+// Debugf prints to stderr with a [DEBUG] indicator using fmt.Sprintf.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func (l Logger) Debugf(template string, args ...any) {
+	l.Sugar().Debugf(template, args...)
+}
+
+// Tracef prints to stderr with a [TRACE] indicator using fmt.Sprintf.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func (l Logger) Tracef(template string, args ...any) {
+	s := l.Sugar()
+	if enabled(s, cliolog.TraceLevel) {
+		s.Desugar().Log(cliolog.TraceLevel, fmt.Sprintf(template, args...))
+	}
+}
+
+// Logln prints to stdout with no prefix using fmt.Sprintln.
+func (l Logger) Logln(args ...any) {
+	l.Sugar().Named(cliolog.NoPrefixName).Infoln(args...)
+}
+
+// Infoln prints to stderr with an [i] indicator using fmt.Sprintln.
+func (l Logger) Infoln(args ...any) {
+	l.Sugar().Infoln(args...)
+}
+
+// Successln prints to stderr with a [✔] indicator using fmt.Sprintln.
+func (l Logger) Successln(args ...any) {
+	withKind(l.Sugar(), cliolog.SuccessKind).Infoln(args...)
+}
+
+// Errorln prints to stderr with a [✘] indicator using fmt.Sprintln.
+func (l Logger) Errorln(args ...any) {
+	l.Sugar().Errorln(args...)
+}
+
+// Warnln prints to stderr with a [!] indicator using fmt.Sprintln.
+func (l Logger) Warnln(args ...any) {
+	l.Sugar().Warnln(args...)
+}
+
+// Debugln prints to stderr with a [DEBUG] indicator using fmt.Sprintln.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func (l Logger) Debugln(args ...any) {
+	l.Sugar().Debugln(args...)
+}
+
+// Traceln prints to stderr with a [TRACE] indicator using fmt.Sprintln.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func (l Logger) Traceln(args ...any) {
+	s := l.Sugar()
+	if enabled(s, cliolog.TraceLevel) {
+		s.Desugar().Log(cliolog.TraceLevel, sprintln(args...))
+	}
+}
+
+// Log prints to stdout with no prefix using fmt.Sprint.
+func (l Logger) Log(args ...any) {
+	l.Sugar().Named(cliolog.NoPrefixName).Info(args...)
+}
+
+// Info prints to stderr with an [i] indicator using fmt.Sprint.
+func (l Logger) Info(args ...any) {
+	l.Sugar().Info(args...)
+}
+
+// Success prints to stderr with a [✔] indicator using fmt.Sprint.
+func (l Logger) Success(args ...any) {
+	withKind(l.Sugar(), cliolog.SuccessKind).Info(args...)
+}
+
+// Error prints to stderr with a [✘] indicator using fmt.Sprint.
+func (l Logger) Error(args ...any) {
+	l.Sugar().Error(args...)
+}
+
+// Warn prints to stderr with a [!] indicator using fmt.Sprint.
+func (l Logger) Warn(args ...any) {
+	l.Sugar().Warn(args...)
+}
+
+// Debug prints to stderr with a [DEBUG] indicator using fmt.Sprint.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func (l Logger) Debug(args ...any) {
+	l.Sugar().Debug(args...)
+}
+
+// Trace prints to stderr with a [TRACE] indicator using fmt.Sprint.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func (l Logger) Trace(args ...any) {
+	s := l.Sugar()
+	if enabled(s, cliolog.TraceLevel) {
+		s.Desugar().Log(cliolog.TraceLevel, fmt.Sprint(args...))
+	}
+}
+
+// Infow prints to stderr with an [i] indicator with additional key-value pairs.
+func (l Logger) Infow(msg string, keysAndValues ...any) {
+	l.Sugar().Infow(msg, keysAndValues...)
+}
+
+// Successw prints to stderr with a [✔] indicator with additional key-value pairs.
+func (l Logger) Successw(msg string, keysAndValues ...any) {
+	withKind(l.Sugar(), cliolog.SuccessKind).Infow(msg, keysAndValues...)
+}
+
+// Errorw prints to stderr with a [✘] indicator with additional key-value pairs.
+func (l Logger) Errorw(msg string, keysAndValues ...any) {
+	l.Sugar().Errorw(msg, keysAndValues...)
+}
+
+// Warnw prints to stderr with a [!] indicator with additional key-value pairs.
+func (l Logger) Warnw(msg string, keysAndValues ...any) {
+	l.Sugar().Warnw(msg, keysAndValues...)
+}
+
+// Debugw prints to stderr with a [DEBUG] indicator with additional key-value pairs.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'debug'.
+func (l Logger) Debugw(msg string, keysAndValues ...any) {
+	l.Sugar().Debugw(msg, keysAndValues...)
+}
+
+// Tracew prints to stderr with a [TRACE] indicator with additional key-value pairs.
+// Messages will be shown if the GRANTED_LOG or CF_LOG environment variable is set to 'trace'.
+func (l Logger) Tracew(msg string, keysAndValues ...any) {
+	s := l.Sugar()
+	if enabled(s, cliolog.TraceLevel) {
+		s.Desugar().Log(cliolog.TraceLevel, msg, sweetenFields(keysAndValues)...)
+	}
+}
@@ -0,0 +1,70 @@
+package clio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLoggerWith(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	l := Named("aws").With("account", "123")
+	l.Infow("my message", "region", "us-east-1")
+
+	got := b.String()
+
+	want := "[i] my message  \tregion:us-east-1\taccount:123\n"
+	if got != want {
+		t.Errorf("Logger.Infow() = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerSuccess(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	Named("aws").Named("sso").Successf("hello %s", "world")
+
+	got := b.String()
+
+	want := "[✔] hello world\n"
+	if got != want {
+		t.Errorf("Logger.Successf() = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerLog(t *testing.T) {
+	var b bytes.Buffer
+	SetOutputWriter(&b)
+	NoColor = true
+
+	Named("aws").Log("my message")
+
+	got := b.String()
+
+	// should print without a prefix.
+	want := "my message\n"
+	if got != want {
+		t.Errorf("Logger.Log() = %q, want %q", got, want)
+	}
+}
+
+func TestLoggerUsesCurrentGlobal(t *testing.T) {
+	l := With("key", "value")
+
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	l.Warn("my message")
+
+	got := b.String()
+
+	want := "[!] my message  \tkey:value\n"
+	if got != want {
+		t.Errorf("Logger.Warn() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,210 @@
+package clio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	// onceMu locks access to the once state.
+	onceMu sync.Mutex
+
+	// onceShown holds the keys of messages shown in this process.
+	onceShown = map[string]bool{}
+
+	// oncePath is the path to the state file used in persistent mode.
+	// If empty, messages are only shown once per process.
+	oncePath string
+
+	// onceCooldown is the time after which a message is shown again in persistent mode.
+	onceCooldown time.Duration
+)
+
+// WarnOnce prints to stderr with a [!] indicator using fmt.Sprint,
+// the first time it is called with key.
+//
+// By default messages are shown once per process. Call PersistOnce to
+// show messages once across invocations of the CLI.
+func WarnOnce(key string, args ...any) {
+	if shouldShowOnce(key, zapcore.WarnLevel) {
+		Warn(args...)
+	}
+}
+
+// InfoOnce prints to stderr with an [i] indicator using fmt.Sprint,
+// the first time it is called with key.
+//
+// By default messages are shown once per process. Call PersistOnce to
+// show messages once across invocations of the CLI.
+func InfoOnce(key string, args ...any) {
+	if shouldShowOnce(key, zapcore.InfoLevel) {
+		Info(args...)
+	}
+}
+
+// Deprecated prints a deprecation warning for a feature with WarnOnce.
+// removal may be empty if the version that the feature will be removed in isn't known.
+//
+// Example:
+//
+//	clio.Deprecated("the --profile flag", "v0.10.0", "v1.0.0")
+//	// prints:
+//	// [!] the --profile flag is deprecated since v0.10.0 and will be removed in v1.0.0
+func Deprecated(feature, since, removal string) {
+	if removal == "" {
+		removal = "a future release"
+	}
+	WarnOnce("deprecated:"+feature, fmt.Sprintf("%s is deprecated since %s and will be removed in %s", feature, since, removal))
+}
+
+// PersistOnce stores the keys of messages shown with WarnOnce, InfoOnce and
+// Deprecated in a state file at path, so that they are shown once across
+// invocations of the CLI rather than once per process. Messages are shown
+// again after the cooldown has passed. If cooldown is zero, messages are
+// only ever shown once.
+//
+// It returns a function which restores the previous configuration.
+//
+// Example:
+//
+//	path, err := clio.OnceStatePath("granted")
+//	if err == nil {
+//		clio.PersistOnce(path, 7*24*time.Hour)
+//	}
+func PersistOnce(path string, cooldown time.Duration) func() {
+	onceMu.Lock()
+	defer onceMu.Unlock()
+
+	prevPath, prevCooldown := oncePath, onceCooldown
+	oncePath, onceCooldown = path, cooldown
+
+	return func() {
+		onceMu.Lock()
+		defer onceMu.Unlock()
+		oncePath, onceCooldown = prevPath, prevCooldown
+	}
+}
+
+// OnceStatePath returns the default path of the state file used with
+// PersistOnce, under the user's state directory. The state directory is
+// $XDG_STATE_HOME if set, %LocalAppData% on Windows, and ~/.local/state otherwise.
+func OnceStatePath(appName string) (string, error) {
+	dir, err := stateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName, "clio-once.json"), nil
+}
+
+func stateDir() (string, error) {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		return os.UserCacheDir()
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "state"), nil
+}
+
+// shouldShowOnce returns true if the message with the provided key should be shown,
+// and records that it has been shown. Messages at a level which isn't enabled
+// aren't recorded, so that they're shown when the level is enabled.
+func shouldShowOnce(key string, level zapcore.Level) bool {
+	if !S().Desugar().Core().Enabled(level) {
+		return false
+	}
+
+	onceMu.Lock()
+	defer onceMu.Unlock()
+
+	if onceShown[key] {
+		return false
+	}
+	onceShown[key] = true
+
+	if oncePath == "" {
+		return true
+	}
+
+	state, err := readOnceState(oncePath)
+	if err != nil {
+		Debugw("error reading once state file", "path", oncePath, "error", err)
+		if state == nil {
+			return true
+		}
+	}
+
+	now := time.Now()
+	if last, ok := state[key]; ok && (onceCooldown == 0 || now.Sub(last) < onceCooldown) {
+		return false
+	}
+
+	state[key] = now
+	if err := writeOnceState(oncePath, state); err != nil {
+		Debugw("error writing once state file", "path", oncePath, "error", err)
+	}
+	return true
+}
+
+// readOnceState reads the time each message was last shown from the state file.
+// If the file can't be decoded, an empty state is returned along with the
+// error, so that the corrupt file is replaced when the state is written.
+func readOnceState(path string) (map[string]time.Time, error) {
+	state := map[string]time.Time{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return map[string]time.Time{}, err
+	}
+	return state, nil
+}
+
+// writeOnceState writes the state file, creating its directory if it doesn't exist.
+// The state is written to a temporary file which replaces the state file, so that
+// concurrent invocations of the CLI never read a partially written file.
+func writeOnceState(path string, state map[string]time.Time) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// the temporary file is removed if it isn't renamed.
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
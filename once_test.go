@@ -0,0 +1,138 @@
+package clio
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWarnOnce(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	WarnOnce("test-warn-once", "my message")
+	WarnOnce("test-warn-once", "my message")
+	InfoOnce("test-info-once", "my other message")
+
+	got := b.String()
+
+	want := "[!] my message\n[i] my other message\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestPersistOnce(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	path := filepath.Join(t.TempDir(), "app", "clio-once.json")
+	restore := PersistOnce(path, time.Hour)
+	defer restore()
+
+	Deprecated("the --profile flag", "v0.10.0", "v1.0.0")
+
+	// simulate a new invocation of the CLI by clearing the in-process state.
+	onceMu.Lock()
+	delete(onceShown, "deprecated:the --profile flag")
+	onceMu.Unlock()
+
+	Deprecated("the --profile flag", "v0.10.0", "v1.0.0")
+
+	got := b.String()
+
+	want := "[!] the --profile flag is deprecated since v0.10.0 and will be removed in v1.0.0\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	state, err := readOnceState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state["deprecated:the --profile flag"]; !ok {
+		t.Errorf("state = %v, want the deprecation key", state)
+	}
+}
+
+func TestPersistOnceCooldown(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	path := filepath.Join(t.TempDir(), "clio-once.json")
+	restore := PersistOnce(path, time.Hour)
+	defer restore()
+
+	// the message was last shown longer ago than the cooldown.
+	if err := writeOnceState(path, map[string]time.Time{"test-cooldown": time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	InfoOnce("test-cooldown", "shown again")
+
+	if got, want := b.String(), "[i] shown again\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestOnceLevelDisabled(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	path := filepath.Join(t.TempDir(), "clio-once.json")
+	restore := PersistOnce(path, time.Hour)
+	defer restore()
+
+	restoreLevel := SetLevelFromString("error")
+	WarnOnce("test-level-disabled", "hidden")
+	restoreLevel()
+
+	// the message wasn't shown, so it's shown once the level is enabled.
+	WarnOnce("test-level-disabled", "shown")
+
+	if got, want := b.String(), "[!] shown\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestPersistOnceCorruptState(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	path := filepath.Join(t.TempDir(), "clio-once.json")
+	restore := PersistOnce(path, time.Hour)
+	defer restore()
+
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	InfoOnce("test-corrupt-state", "my message")
+
+	// the corrupt state file is replaced.
+	state, err := readOnceState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := state["test-corrupt-state"]; !ok {
+		t.Errorf("state = %v, want the message key", state)
+	}
+	if got, want := b.String(), "[i] my message\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("state directory has %d files, want only the state file", len(entries))
+	}
+}
@@ -0,0 +1,32 @@
+package clio
+
+import "github.com/common-fate/clio/cliolog"
+
+// EnableRecorder enables a flight recorder, which keeps the most recent
+// messages that weren't printed to the console, such as debug messages when
+// the logging level is info. When an error is logged, the recorded messages
+// are dumped to the console under a "recent debug output" header and/or to
+// the file log, as configured by cfg.
+//
+// It returns a function which disables the recorder.
+//
+// Example:
+//
+//	clio.EnableRecorder(cliolog.RecorderConfig{Size: 50, Console: true, File: true})
+func EnableRecorder(cfg cliolog.RecorderConfig) func() {
+	return Configure(cliolog.WithRecorder(cliolog.NewRecorder(cfg)))
+}
+
+// DumpRecent dumps the messages kept by the flight recorder, if one has been
+// enabled with EnableRecorder, and then clears them. Messages are dumped
+// automatically when an error is logged, so DumpRecent is only needed to show
+// them in other situations.
+func DumpRecent() {
+	globalMu.RLock()
+	r := globalOptions.Recorder
+	globalMu.RUnlock()
+
+	if r != nil {
+		r.Dump()
+	}
+}
@@ -0,0 +1,79 @@
+package clio
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecorder(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := EnableRecorder(cliolog.RecorderConfig{Size: 2, Console: true})
+	defer restore()
+
+	Debug("dropped")
+	Info("shown")
+	Debug("first")
+	Debugw("second", "key", "value")
+	Error("failed")
+	// the recorder is cleared after it's dumped.
+	Error("failed again")
+
+	want := "[i] shown\n" +
+		"recent debug output:\n" +
+		"[DEBUG] first\n" +
+		"[DEBUG] second  \tkey:value\n" +
+		"[✘] failed\n" +
+		"[✘] failed again\n"
+	if got := b.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRecorderDoesNotCount(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := EnableRecorder(cliolog.RecorderConfig{Console: true})
+	defer restore()
+	ResetCounts()
+
+	Debug("recorded")
+
+	if got := Counts().Debug; got != 0 {
+		t.Errorf("debug count = %d, want 0", got)
+	}
+}
+
+func TestRecorderFile(t *testing.T) {
+	var console, file bytes.Buffer
+	SetWriter(&console)
+	NoColor = true
+	ws := zapcore.AddSync(&file)
+
+	restoreFile := Configure(func(o *cliolog.Options) { o.FileWriteSyncer = &ws })
+	defer restoreFile()
+	restore := EnableRecorder(cliolog.RecorderConfig{File: true})
+	defer restore()
+
+	Debug("already in file")
+	Trace("only recorded")
+	DumpRecent()
+
+	if strings.Contains(console.String(), "recent debug output") {
+		t.Errorf("console = %q, want no recent debug output", console.String())
+	}
+	if got := strings.Count(file.String(), "already in file"); got != 1 {
+		t.Errorf("file log contains debug message %d times, want 1", got)
+	}
+	if !strings.Contains(file.String(), "only recorded") {
+		t.Errorf("file log = %q, want it to contain the trace message", file.String())
+	}
+}
@@ -0,0 +1,38 @@
+package clio
+
+import "github.com/common-fate/clio/cliolog"
+
+// redactor redacts secrets from messages logged by the global logger.
+var redactor = cliolog.NewRedactor()
+
+// RegisterSecret registers a value, such as a session token, which is
+// replaced with [REDACTED] wherever it appears in messages and fields,
+// including in the file log and in clierr messages.
+//
+// As well as registered secrets, AWS keys, bearer tokens, JWTs and
+// private keys are detected and redacted, and fields with keys matching
+// *token* and *password* are always redacted.
+//
+// Example:
+//
+//	creds, err := provider.Retrieve(ctx)
+//	if err == nil {
+//		clio.RegisterSecret(creds.SecretAccessKey)
+//		clio.RegisterSecret(creds.SessionToken)
+//	}
+func RegisterSecret(value string) {
+	redactor.AddSecret(value)
+}
+
+// RegisterSecretKey adds a key name rule, such as '*secret*'. Fields with
+// keys matching the rule are replaced with [REDACTED]. Rules are matched
+// case-insensitively with path.Match.
+func RegisterSecretKey(pattern string) {
+	redactor.AddKey(pattern)
+}
+
+// Redact returns s with any secrets replaced with [REDACTED].
+// It can be used to redact output which isn't printed with clio.
+func Redact(s string) string {
+	return redactor.Redact(s)
+}
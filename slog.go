@@ -0,0 +1,38 @@
+//go:build go1.21
+
+package clio
+
+import (
+	"log"
+	"log/slog"
+
+	"github.com/common-fate/clio/cliolog"
+)
+
+// SetAsSlogDefault sets a handler which writes to the global clio logger
+// as the default log/slog logger, so that calls such as slog.Info are
+// printed with clio symbols and colors, and filtered using clio.Level.
+// It returns a function which restores the previous default logger.
+// It's only available when building with Go 1.21 or later.
+func SetAsSlogDefault() func() {
+	prev := slog.Default()
+
+	// slog.SetDefault also redirects the standard library's log package,
+	// so its configuration needs to be restored too.
+	flags := log.Flags()
+	out := log.Writer()
+
+	slog.SetDefault(slog.New(SlogHandler()))
+
+	return func() {
+		slog.SetDefault(prev)
+		log.SetFlags(flags)
+		log.SetOutput(out)
+	}
+}
+
+// SlogHandler returns a slog.Handler which writes to the global clio logger.
+// It's only available when building with Go 1.21 or later.
+func SlogHandler() slog.Handler {
+	return cliolog.NewSlogHandler(cliolog.SlogHandlerOptions{Core: globalCore{}})
+}
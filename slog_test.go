@@ -0,0 +1,60 @@
+//go:build go1.21
+
+package clio
+
+import (
+	"bytes"
+	"log"
+	"log/slog"
+	"testing"
+)
+
+func TestSetAsSlogDefault(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetAsSlogDefault()
+	defer restore()
+
+	restoreLevel := SetLevelFromString("info")
+	defer restoreLevel()
+
+	slog.Debug("hidden")
+	slog.Info("my message", "key", "value")
+	slog.With("account", "123").WithGroup("req").Warn("my warning", "id", 1)
+	slog.Error("my error", slog.Group("http", "status", 500))
+
+	got := b.String()
+
+	want := "[i] my message  \tkey:value\n" +
+		"[!] my warning  \treq.id:1\taccount:123\n" +
+		"[✘] my error  \thttp:{status:500}\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	restore()
+
+	if log.Flags() != log.LstdFlags {
+		t.Errorf("log flags = %d after restore, want %d", log.Flags(), log.LstdFlags)
+	}
+}
+
+func TestSlogWithUsesCurrentWriter(t *testing.T) {
+	var before, after bytes.Buffer
+	SetWriter(&before)
+	NoColor = true
+
+	logger := slog.New(SlogHandler()).With("account", "123")
+
+	SetWriter(&after)
+	logger.Info("my message")
+
+	if before.Len() > 0 {
+		t.Errorf("output written to the previous writer: %q", before.String())
+	}
+	if got, want := after.String(), "[i] my message  \taccount:123\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
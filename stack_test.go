@@ -0,0 +1,76 @@
+package clio
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestErrorStacks(t *testing.T) {
+	var console, file bytes.Buffer
+	SetWriter(&console)
+	NoColor = true
+	ws := zapcore.AddSync(&file)
+
+	restoreFile := Configure(func(o *cliolog.Options) { o.FileWriteSyncer = &ws })
+	defer restoreFile()
+	restore := SetLevelFromString("debug")
+	defer restore()
+
+	Errorf("my error")
+	_, _, line, _ := runtime.Caller(0)
+	line--
+
+	want := fmt.Sprintf("[✘] my error stack_test.go:%d\n", line) +
+		fmt.Sprintf("    at clio.TestErrorStacks (stack_test.go:%d)\n", line)
+	if got := console.String(); got != want {
+		t.Errorf("console = %q, want %q", got, want)
+	}
+
+	// the file log has the full stack trace.
+	for _, frame := range []string{"clio.Errorf", "clio.TestErrorStacks", "testing.tRunner"} {
+		if !strings.Contains(file.String(), frame) {
+			t.Errorf("file log = %q, want stack trace containing %q", file.String(), frame)
+		}
+	}
+}
+
+func TestErrorStacksNotShownAtInfo(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetLevelFromString("info")
+	defer restore()
+
+	Errorf("my error")
+
+	if got, want := b.String(), "[✘] my error\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestErrorStacksNamedLevels(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restore := SetLevelFromString("info,aws=debug")
+	defer restore()
+
+	Named("aws").Error("aws error")
+	Named("other").Error("other error")
+
+	got := b.String()
+	if !strings.Contains(got, "at clio.TestErrorStacksNamedLevels") {
+		t.Errorf("output = %q, want a stack trace for the aws logger", got)
+	}
+	if !strings.HasSuffix(got, "[✘] other error\n") {
+		t.Errorf("output = %q, want no stack trace for the other logger", got)
+	}
+}
@@ -0,0 +1,123 @@
+package clio
+
+import (
+	"bytes"
+	"log"
+	"strings"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap/zapcore"
+)
+
+// StdLogName is the logger name used for messages redirected from the
+// standard library's log package. Use it with SetNamedLevel or a level
+// spec such as CF_LOG=info,stdlog=warn to filter these messages.
+const StdLogName = "stdlog"
+
+// StdLogOption configures RedirectStdLog.
+type StdLogOption func(*stdLogWriter)
+
+// WithLevelDetection detects level prefixes in messages written to the
+// standard library's log package, such as "[DEBUG]", "[WARN]" or "ERROR:".
+// The prefix is removed and the message is logged at the detected level.
+// Messages without a prefix are logged at the level provided to RedirectStdLog.
+func WithLevelDetection() StdLogOption {
+	return func(w *stdLogWriter) {
+		w.detectLevel = true
+	}
+}
+
+// RedirectStdLog redirects output from the standard library's log package,
+// such as log.Printf calls made by third-party packages, to clio at the
+// provided level. The log package's timestamp and prefix are removed, as
+// clio formats messages itself.
+//
+// It returns a function which restores the previous log package configuration.
+func RedirectStdLog(level zapcore.Level, opts ...StdLogOption) func() {
+	w := &stdLogWriter{level: level}
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	flags := log.Flags()
+	prefix := log.Prefix()
+	out := log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(w)
+
+	return func() {
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(out)
+	}
+}
+
+// stdLogWriter is an io.Writer which logs messages written by the
+// standard library's log package. The log package calls Write once for
+// each message.
+type stdLogWriter struct {
+	level       zapcore.Level
+	detectLevel bool
+}
+
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimSuffix(p, []byte("\n")))
+	level := w.level
+	if w.detectLevel {
+		if lvl, rest, ok := parseLevelPrefix(msg); ok {
+			level, msg = lvl, rest
+		}
+	}
+
+	logger := Named(StdLogName).Sugar().Desugar()
+	if ce := logger.Check(level, msg); ce != nil {
+		ce.Write()
+	}
+	return len(p), nil
+}
+
+// stdLogLevels are the level prefixes detected in messages from the log package.
+// Levels above error are logged at ErrorLevel, so that a message from a
+// third-party package can't cause a panic or exit the program.
+var stdLogLevels = map[string]zapcore.Level{
+	"trace":   cliolog.TraceLevel,
+	"debug":   zapcore.DebugLevel,
+	"info":    zapcore.InfoLevel,
+	"warn":    zapcore.WarnLevel,
+	"warning": zapcore.WarnLevel,
+	"err":     zapcore.ErrorLevel,
+	"error":   zapcore.ErrorLevel,
+	"dpanic":  zapcore.ErrorLevel,
+	"panic":   zapcore.ErrorLevel,
+	"fatal":   zapcore.ErrorLevel,
+}
+
+// parseLevelPrefix parses a level prefix such as "[DEBUG]" or "ERROR:" from msg.
+// It returns the level and the message with the prefix removed.
+func parseLevelPrefix(msg string) (zapcore.Level, string, bool) {
+	trimmed := strings.TrimLeft(msg, " \t")
+
+	var word, rest string
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		end := strings.IndexByte(trimmed, ']')
+		if end < 0 {
+			return 0, msg, false
+		}
+		word, rest = trimmed[1:end], trimmed[end+1:]
+	default:
+		end := strings.IndexByte(trimmed, ':')
+		if end < 0 {
+			return 0, msg, false
+		}
+		word, rest = trimmed[:end], trimmed[end+1:]
+	}
+
+	lvl, ok := stdLogLevels[strings.ToLower(word)]
+	if !ok {
+		return 0, msg, false
+	}
+	return lvl, strings.TrimLeft(rest, " \t"), true
+}
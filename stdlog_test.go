@@ -0,0 +1,67 @@
+package clio
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRedirectStdLog(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	restoreLevel := SetLevelFromString("info")
+	defer restoreLevel()
+
+	restore := RedirectStdLog(zapcore.InfoLevel, WithLevelDetection())
+
+	log.Printf("hello %s", "world")
+	log.Print("[DEBUG] hidden")
+	log.Print("[WARN] a warning")
+	log.Print("ERROR: an error")
+	log.Print("http: not a level")
+	log.Print("panic: not a panic")
+	log.Print("[FATAL] not an exit")
+
+	restore()
+
+	got := b.String()
+
+	want := "[i] hello world\n[!] a warning\n[✘] an error\n[i] http: not a level\n[✘] not a panic\n[✘] not an exit\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if log.Flags() != log.LstdFlags {
+		t.Errorf("log flags = %d, want %d", log.Flags(), log.LstdFlags)
+	}
+}
+
+func TestParseLevelPrefix(t *testing.T) {
+	tests := []struct {
+		msg       string
+		wantLevel zapcore.Level
+		wantMsg   string
+		wantOK    bool
+	}{
+		{msg: "[DEBUG] message", wantLevel: zapcore.DebugLevel, wantMsg: "message", wantOK: true},
+		{msg: "[warning] message", wantLevel: zapcore.WarnLevel, wantMsg: "message", wantOK: true},
+		{msg: "ERR: message", wantLevel: zapcore.ErrorLevel, wantMsg: "message", wantOK: true},
+		{msg: "[TRACE] message", wantLevel: -2, wantMsg: "message", wantOK: true},
+		{msg: "panic: message", wantLevel: zapcore.ErrorLevel, wantMsg: "message", wantOK: true},
+		{msg: "fatal: message", wantLevel: zapcore.ErrorLevel, wantMsg: "message", wantOK: true},
+		{msg: "[FATAL] message", wantLevel: zapcore.ErrorLevel, wantMsg: "message", wantOK: true},
+		{msg: "message", wantMsg: "message"},
+		{msg: "[unknown] message", wantMsg: "[unknown] message"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			lvl, msg, ok := parseLevelPrefix(tt.msg)
+			if ok != tt.wantOK || msg != tt.wantMsg || (ok && lvl != tt.wantLevel) {
+				t.Errorf("parseLevelPrefix() = %s, %q, %v, want %s, %q, %v", lvl, msg, ok, tt.wantLevel, tt.wantMsg, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package clio
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestSetTimeFormatFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		give string
+		want string
+	}{
+		{name: "none", give: "none", want: `^\[i\] my message\n$`},
+		{name: "wall", give: "wall", want: `^\d{2}:\d{2}:\d{2}\.\d{3} \[i\] my message\n$`},
+		{name: "elapsed", give: "elapsed", want: `^\+\d+\.\d{3}s \[i\] my message\n$`},
+		{name: "delta", give: "DELTA", want: `^\+\d+\.\d{3}s \[i\] my message\n$`},
+		{name: "invalid", give: "nope", want: `^\[i\] my message\n$`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			SetWriter(&b)
+			NoColor = true
+
+			t.Setenv("CF_LOG_TIME", tt.give)
+			restore := SetTimeFormatFromEnv("CF_LOG_TIME")
+			defer restore()
+
+			Info("my message")
+
+			if got := b.String(); !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("output = %q, want match for %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeFormatOutputMessages(t *testing.T) {
+	var status, output bytes.Buffer
+	SetWriter(&status)
+	SetOutputWriter(&output)
+	NoColor = true
+
+	t.Setenv("CF_LOG_TIME", "wall")
+	restore := SetTimeFormatFromEnv("CF_LOG_TIME")
+	defer restore()
+
+	Log("piped output")
+
+	// output messages are never prefixed with the time, so that they can be piped.
+	if got, want := output.String(), "piped output\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,25 @@
+package clio
+
+import (
+	"io"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewWriter returns an io.WriteCloser which logs each line written to it
+// at the provided level. It can be used to show diagnostics from subprocesses
+// and libraries which write to an io.Writer in the same format as other
+// clio messages.
+//
+// Close must be called to log any partial line remaining in the buffer.
+//
+// Example:
+//
+//	w := clio.NewWriter(zapcore.DebugLevel, cliolog.WriterPrefix("terraform"))
+//	defer w.Close()
+//	cmd.Stderr = w
+func NewWriter(level zapcore.Level, opts ...cliolog.WriterOption) io.WriteCloser {
+	return cliolog.NewWriter(zap.New(globalCore{}), level, opts...)
+}
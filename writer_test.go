@@ -0,0 +1,34 @@
+package clio
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/common-fate/clio/cliolog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewWriter(t *testing.T) {
+	var b bytes.Buffer
+	SetWriter(&b)
+	NoColor = true
+
+	w := NewWriter(zapcore.InfoLevel, cliolog.WriterPrefix("cmd"), cliolog.WriterFields(zap.Int("pid", 1)))
+
+	_, _ = w.Write([]byte("first li"))
+	_, _ = w.Write([]byte("ne\r\nsecond line\n\n"))
+	_, _ = w.Write([]byte("partial"))
+
+	if got, want := b.String(), "[i] cmd: first line  \tpid:1\n[i] cmd: second line  \tpid:1\n"; got != want {
+		t.Errorf("output before Close() = %q, want %q", got, want)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := b.String(), "[i] cmd: first line  \tpid:1\n[i] cmd: second line  \tpid:1\n[i] cmd: partial  \tpid:1\n"; got != want {
+		t.Errorf("output after Close() = %q, want %q", got, want)
+	}
+}